@@ -0,0 +1,204 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"ariga.io/entimport/internal/entimport"
+
+	"entgo.io/contrib/schemast"
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"github.com/stretchr/testify/require"
+)
+
+// stubImporter returns the given states on subsequent calls, and cancels the watch once the last one is returned.
+type stubImporter struct {
+	mu     sync.Mutex
+	calls  int
+	states [][]schemast.Mutator
+	cancel context.CancelFunc
+}
+
+func (s *stubImporter) SchemaMutations(context.Context) ([]schemast.Mutator, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.calls >= len(s.states)-1 {
+		s.cancel()
+		return s.states[len(s.states)-1], nil
+	}
+	s.calls++
+	return s.states[s.calls-1], nil
+}
+
+func userSchema(fields ...ent.Field) []schemast.Mutator {
+	return []schemast.Mutator{
+		&schemast.UpsertSchema{
+			Name:   "User",
+			Fields: append([]ent.Field{field.Int("id")}, fields...),
+		},
+	}
+}
+
+func TestRegenerate(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	i := &stubImporter{
+		states: [][]schemast.Mutator{
+			userSchema(),
+			userSchema(),
+			userSchema(field.String("name")),
+		},
+		cancel: func() {},
+	}
+	changed, err := regenerate(ctx, i, dir)
+	require.NoError(t, err)
+	require.Equal(t, []string{"user.go"}, changed)
+	info, err := os.Stat(filepath.Join(dir, "user.go"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0644), info.Mode().Perm())
+	changed, err = regenerate(ctx, i, dir)
+	require.NoError(t, err)
+	require.Empty(t, changed)
+	changed, err = regenerate(ctx, i, dir)
+	require.NoError(t, err)
+	require.Equal(t, []string{"user.go"}, changed)
+	buf, err := os.ReadFile(filepath.Join(dir, "user.go"))
+	require.NoError(t, err)
+	require.Contains(t, string(buf), `field.String("name")`)
+}
+
+func TestWatch(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	dir := t.TempDir()
+	i := &stubImporter{
+		states: [][]schemast.Mutator{
+			userSchema(),
+			userSchema(field.String("name")),
+		},
+		cancel: cancel,
+	}
+	require.NoError(t, watch(ctx, i, 10*time.Millisecond, dir))
+	require.ErrorIs(t, ctx.Err(), context.Canceled)
+	buf, err := os.ReadFile(filepath.Join(dir, "user.go"))
+	require.NoError(t, err)
+	require.Contains(t, string(buf), `field.String("name")`)
+}
+
+func TestWatchQuiet(t *testing.T) {
+	defer func(l *log.Logger) { logger = l }(logger)
+	for _, quiet := range []bool{false, true} {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		i := &stubImporter{
+			states: [][]schemast.Mutator{
+				userSchema(),
+				userSchema(field.String("name")),
+			},
+			cancel: cancel,
+		}
+		var buf bytes.Buffer
+		logger = newLogger(&buf, quiet)
+		require.NoError(t, watch(ctx, i, 10*time.Millisecond, t.TempDir()))
+		cancel()
+		if quiet {
+			require.Empty(t, buf.String())
+		} else {
+			require.Contains(t, buf.String(), "entimport: updated ")
+		}
+	}
+}
+
+func TestDiffSchema(t *testing.T) {
+	dir := t.TempDir()
+	_, err := entimport.WriteSchema(userSchema(field.String("name")), entimport.WithSchemaPath(dir))
+	require.NoError(t, err)
+	diff, err := diffSchema(userSchema(field.String("name")), dir)
+	require.NoError(t, err)
+	require.Empty(t, diff)
+	diff, err = diffSchema(userSchema(field.String("name").Optional()), dir)
+	require.NoError(t, err)
+	fn := filepath.Join(dir, "user.go")
+	require.Contains(t, diff, "--- "+fn+"\n+++ "+fn+"\n")
+	require.Contains(t, diff, `-	return []ent.Field{field.Int("id"), field.String("name")}`)
+	require.Contains(t, diff, `+	return []ent.Field{field.Int("id"), field.String("name").Optional()}`)
+	buf, err := os.ReadFile(fn)
+	require.NoError(t, err)
+	require.NotContains(t, string(buf), "Optional")
+}
+
+func TestPrintFiles(t *testing.T) {
+	dir := t.TempDir()
+	files, err := entimport.RenderSchema(userSchema(field.String("name")), entimport.WithSchemaPath(dir))
+	require.NoError(t, err)
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+	var b bytes.Buffer
+	require.NoError(t, printFiles(&b, files, "ent/schema"))
+	require.True(t, bytes.HasPrefix(b.Bytes(), []byte("// ent/schema/user.go\n")))
+	require.Contains(t, b.String(), `return []ent.Field{field.Int("id"), field.String("name")}`)
+
+	// The schema is rendered over a copy of the existing files, which are left untouched.
+	dir = filepath.Join(t.TempDir(), "schema")
+	require.NoError(t, os.Mkdir(dir, 0755))
+	_, err = entimport.WriteSchema(userSchema(), entimport.WithSchemaPath(dir))
+	require.NoError(t, err)
+	current, err := readFiles(dir)
+	require.NoError(t, err)
+	files, err = entimport.RenderSchema(userSchema(field.String("name")), entimport.WithSchemaPath(dir))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Contains(t, string(files["user.go"]), `return []ent.Field{field.Int("id"), field.String("name")}`)
+	updated, err := readFiles(dir)
+	require.NoError(t, err)
+	require.Equal(t, current, updated)
+	entries, err = os.ReadDir(filepath.Dir(dir))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+}
+
+func TestPrintSummary(t *testing.T) {
+	dir := t.TempDir()
+	mutations := []schemast.Mutator{
+		&schemast.UpsertSchema{
+			Name:   "User",
+			Fields: []ent.Field{field.Int("id"), field.String("name")},
+			Edges:  []ent.Edge{edge.To("pets", ent.Schema.Type)},
+		},
+		&schemast.UpsertSchema{
+			Name:   "Pet",
+			Fields: []ent.Field{field.Int("id")},
+		},
+	}
+	mutations[0].(*schemast.UpsertSchema).Edges[0].Descriptor().Type = "Pet"
+	files, err := entimport.WriteSchema(mutations, entimport.WithSchemaPath(dir))
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, printSummary(&buf, mutations, dir, files))
+	var s summary
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &s))
+	require.Equal(t, summary{
+		Entities: []entity{
+			{Name: "Pet", File: filepath.Join(dir, "pet.go"), Fields: 1, Edges: []string{}},
+			{Name: "User", File: filepath.Join(dir, "user.go"), Fields: 2, Edges: []string{"pets"}},
+		},
+		Files: []string{filepath.Join(dir, "pet.go"), filepath.Join(dir, "user.go")},
+	}, s)
+	require.Contains(t, buf.String(), `"edges": []`)
+}
+
+func TestColumnsFlag(t *testing.T) {
+	c := make(columns)
+	require.NoError(t, c.Set("users.password_hash,users.salt,pets.chip"))
+	require.Equal(t, columns{"users": {"password_hash", "salt"}, "pets": {"chip"}}, c)
+	require.EqualError(t, c.Set("password_hash"), `invalid column "password_hash", expect the format table.column`)
+}
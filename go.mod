@@ -10,7 +10,11 @@ require (
 	github.com/go-sql-driver/mysql v1.6.0
 	github.com/google/uuid v1.3.0
 	github.com/lib/pq v1.10.4
+	github.com/mattn/go-sqlite3 v1.14.10
+	github.com/pmezard/go-difflib v1.0.0
 	github.com/stretchr/testify v1.7.1-0.20210427113832-6241f9ab9942
+	golang.org/x/crypto v0.0.0-20220315160706-3147a52a75dd
+	golang.org/x/tools v0.1.10
 )
 
 require (
@@ -24,7 +28,6 @@ require (
 	github.com/kr/text v0.2.0 // indirect
 	github.com/mitchellh/go-wordwrap v0.0.0-20150314170334-ad45545899c7 // indirect
 	github.com/mitchellh/mapstructure v1.4.3 // indirect
-	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/sergi/go-diff v1.1.0 // indirect
 	github.com/stretchr/objx v0.3.0 // indirect
 	github.com/zclconf/go-cty v1.8.0 // indirect
@@ -33,7 +36,6 @@ require (
 	golang.org/x/mod v0.6.0-dev.0.20220106191415-9b9b3d81d5e3 // indirect
 	golang.org/x/sys v0.0.0-20211205182925-97ca703d548d // indirect
 	golang.org/x/text v0.3.7 // indirect
-	golang.org/x/tools v0.1.10 // indirect
 	golang.org/x/xerrors v0.0.0-20200804184101-5ec99f83aff1 // indirect
 	google.golang.org/protobuf v1.27.1 // indirect
 	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b // indirect
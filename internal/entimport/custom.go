@@ -0,0 +1,97 @@
+package entimport
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"ariga.io/atlas/sql/schema"
+
+	"entgo.io/contrib/schemast"
+	"entgo.io/ent"
+)
+
+// FieldMapper converts an Atlas column to an ent field. The attributes of the column (e.g. its nullability,
+// comment and default value) are applied on the returned field by the importer.
+type FieldMapper func(column *schema.Column) (ent.Field, error)
+
+var (
+	mappersMu sync.RWMutex
+	mappers   = make(map[string]FieldMapper)
+)
+
+// RegisterFieldMapper registers the field mapper of a dialect that is not supported by entimport, for importing
+// the schemas of custom data sources (e.g. a provider registered with mux.RegisterProvider) whose drivers hold
+// the given dialect. The mappers of the supported dialects (MySQL, Postgres and SQLite) cannot be replaced.
+func RegisterFieldMapper(dialect string, mapper FieldMapper) {
+	mappersMu.Lock()
+	defer mappersMu.Unlock()
+	mappers[dialect] = mapper
+}
+
+// fieldMapper returns the field mapper registered for the dialect.
+func fieldMapper(dialect string) (FieldMapper, bool) {
+	mappersMu.RLock()
+	defer mappersMu.RUnlock()
+	m, ok := mappers[dialect]
+	return m, ok
+}
+
+// Custom implements SchemaImporter for data sources of custom dialects, using their registered field mappers.
+type Custom struct {
+	*ImportOptions
+	mapper FieldMapper
+}
+
+// NewCustom - returns a new *Custom for the dialect of the import driver.
+func NewCustom(i *ImportOptions) (SchemaImporter, error) {
+	mapper, ok := fieldMapper(i.driver.Dialect)
+	if !ok {
+		return nil, fmt.Errorf("entimport: unsupported dialect %q, register a field mapper for it with RegisterFieldMapper", i.driver.Dialect)
+	}
+	return &Custom{
+		ImportOptions: i,
+		mapper:        mapper,
+	}, nil
+}
+
+// SchemaMutations implements SchemaImporter.
+func (c *Custom) SchemaMutations(ctx context.Context) ([]schemast.Mutator, error) {
+	inspectOptions := &schema.InspectOptions{
+		Tables: c.tables,
+	}
+	s, err := c.driver.InspectSchema(ctx, c.driver.SchemaName, inspectOptions)
+	if err != nil {
+		return nil, err
+	}
+	tables := s.Tables
+	if c.excludedTables != nil {
+		tables = nil
+		excludedTableNames := make(map[string]bool)
+		for _, t := range c.excludedTables {
+			excludedTableNames[t] = true
+		}
+		// filter out tables that are in excludedTables:
+		for _, t := range s.Tables {
+			if !excludedTableNames[t.Name] {
+				tables = append(tables, t)
+			}
+		}
+	}
+	return schemaMutations(c.ImportOptions, c.field, tables)
+}
+
+func (c *Custom) field(column *schema.Column) (ent.Field, error) {
+	if column.Type == nil || column.Type.Type == nil {
+		return nil, fmt.Errorf("entimport: missing type for column %v", column.Name)
+	}
+	f, err := c.mapper(column)
+	if err != nil {
+		return nil, err
+	}
+	if f == nil {
+		return nil, fmt.Errorf("entimport: unsupported type %q for column %v", column.Type.Raw, column.Name)
+	}
+	applyColumnAttributes(f, column)
+	return f, nil
+}
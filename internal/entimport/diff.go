@@ -0,0 +1,147 @@
+package entimport
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"entgo.io/contrib/schemast"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+)
+
+// Diff returns a human-readable description of the differences between the ent schemas of two lists of mutations,
+// for example, the mutations imported from two databases. Added, removed and changed types are prefixed with "+",
+// "-" and "~" respectively, and the changes of their fields and edges are listed below them.
+// An empty string is returned in case the schemas are equal.
+func Diff(from, to []schemast.Mutator) string {
+	var (
+		b                 strings.Builder
+		fromTs, fromNames = upsertsByName(from)
+		toTs, toNames     = upsertsByName(to)
+	)
+	for _, name := range sortedNames(fromNames, toNames) {
+		f, t := fromTs[name], toTs[name]
+		switch {
+		case f == nil:
+			fmt.Fprintf(&b, "+ type %s\n", name)
+		case t == nil:
+			fmt.Fprintf(&b, "- type %s\n", name)
+		default:
+			if changes := diffType(f, t); len(changes) > 0 {
+				fmt.Fprintf(&b, "~ type %s\n", name)
+				for _, c := range changes {
+					fmt.Fprintf(&b, "  %s\n", c)
+				}
+			}
+		}
+	}
+	return b.String()
+}
+
+// diffType returns the changes of fields and edges between two versions of a type.
+func diffType(from, to *schemast.UpsertSchema) []string {
+	var (
+		changes      []string
+		names        []string
+		fromFs, toFs = make(map[string]*field.Descriptor), make(map[string]*field.Descriptor)
+	)
+	for _, f := range from.Fields {
+		fromFs[f.Descriptor().Name] = f.Descriptor()
+		names = append(names, f.Descriptor().Name)
+	}
+	for _, f := range to.Fields {
+		toFs[f.Descriptor().Name] = f.Descriptor()
+		names = append(names, f.Descriptor().Name)
+	}
+	for _, name := range sortedNames(names) {
+		f, t := fromFs[name], toFs[name]
+		switch {
+		case f == nil:
+			changes = append(changes, fmt.Sprintf("+ field %s %s", name, describeField(t)))
+		case t == nil:
+			changes = append(changes, fmt.Sprintf("- field %s %s", name, describeField(f)))
+		case describeField(f) != describeField(t):
+			changes = append(changes, fmt.Sprintf("~ field %s %s -> %s", name, describeField(f), describeField(t)))
+		}
+	}
+	names = names[:0]
+	fromEs, toEs := make(map[string]*edge.Descriptor), make(map[string]*edge.Descriptor)
+	for _, e := range from.Edges {
+		fromEs[e.Descriptor().Name] = e.Descriptor()
+		names = append(names, e.Descriptor().Name)
+	}
+	for _, e := range to.Edges {
+		toEs[e.Descriptor().Name] = e.Descriptor()
+		names = append(names, e.Descriptor().Name)
+	}
+	for _, name := range sortedNames(names) {
+		f, t := fromEs[name], toEs[name]
+		switch {
+		case f == nil:
+			changes = append(changes, fmt.Sprintf("+ edge %s %s", name, describeEdge(t)))
+		case t == nil:
+			changes = append(changes, fmt.Sprintf("- edge %s %s", name, describeEdge(f)))
+		case describeEdge(f) != describeEdge(t):
+			changes = append(changes, fmt.Sprintf("~ edge %s %s -> %s", name, describeEdge(f), describeEdge(t)))
+		}
+	}
+	return changes
+}
+
+// describeField returns the type and the modifiers of a field, for example: "string optional unique".
+func describeField(d *field.Descriptor) string {
+	s := d.Info.String()
+	if d.Optional {
+		s += " optional"
+	}
+	if d.Unique {
+		s += " unique"
+	}
+	return s
+}
+
+// describeEdge returns the direction, the type and the modifiers of an edge, for example: "to Pet unique".
+func describeEdge(d *edge.Descriptor) string {
+	s := "to " + d.Type
+	if d.Inverse {
+		s = "from " + d.Type
+	}
+	if d.Unique {
+		s += " unique"
+	}
+	return s
+}
+
+// upsertsByName returns the upsert mutations by their type names, and the names.
+func upsertsByName(mutations []schemast.Mutator) (map[string]*schemast.UpsertSchema, []string) {
+	var (
+		names []string
+		types = make(map[string]*schemast.UpsertSchema, len(mutations))
+	)
+	for _, m := range mutations {
+		if u, ok := m.(*schemast.UpsertSchema); ok {
+			types[u.Name] = u
+			names = append(names, u.Name)
+		}
+	}
+	return types, names
+}
+
+// sortedNames returns the sorted names from all given lists, without duplicates.
+func sortedNames(lists ...[]string) []string {
+	var (
+		names []string
+		seen  = make(map[string]bool)
+	)
+	for _, l := range lists {
+		for _, n := range l {
+			if !seen[n] {
+				seen[n] = true
+				names = append(names, n)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
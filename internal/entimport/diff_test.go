@@ -0,0 +1,26 @@
+package entimport_test
+
+import (
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/entimport/internal/entimport"
+
+	"entgo.io/ent/dialect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	from := importMutations(t, dialect.MySQL, MockMySQLO2MTwoTypes())
+	require.Empty(t, entimport.Diff(from, from))
+	changed := MockMySQLO2MTwoTypes()
+	users := changed.Tables[0]
+	users.Columns = append(users.Columns, &schema.Column{
+		Name: "email",
+		Type: &schema.ColumnType{Type: &schema.StringType{T: "varchar", Size: 255}, Raw: "varchar(255)", Null: true},
+	})
+	to := importMutations(t, dialect.MySQL, changed)
+	require.Equal(t, "~ type User\n  + field email string optional\n", entimport.Diff(from, to))
+	require.Equal(t, "~ type User\n  - field email string optional\n", entimport.Diff(to, from))
+	require.Equal(t, "- type Pet\n- type User\n", entimport.Diff(from, nil))
+}
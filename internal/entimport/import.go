@@ -1,9 +1,24 @@
 package entimport
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"ariga.io/atlas/sql/schema"
 	"ariga.io/entimport/internal/mux"
@@ -14,6 +29,8 @@ import (
 	"entgo.io/ent/dialect/entsql"
 	entschema "entgo.io/ent/schema"
 	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
 	"github.com/go-openapi/inflect"
 )
 
@@ -23,8 +40,14 @@ const (
 	from
 )
 
+// entImportPath is the canonical import path of the ent module.
+const entImportPath = "entgo.io/ent"
+
 var joinTableErr = errors.New("entimport: join tables must be inspected with ref tables - append `tables` flag")
 
+// ErrNoTables is returned by SchemaImporter in case the inspected schema has no tables to import.
+var ErrNoTables = errors.New("entimport: no tables found to import")
+
 type (
 	edgeDir int
 
@@ -35,6 +58,10 @@ type (
 		uniqueEdgeFromParent bool
 		refName              string
 		edgeField            string
+		edgeColumn           string
+		source               string // The constraint or the join table the relation is derived from.
+		onDelete             entsql.ReferenceOption
+		comment              string // The comment of the edge field, set on the edge that owns it.
 	}
 
 	// fieldFunc receives an Atlas column and converts it to an Ent field.
@@ -48,14 +75,89 @@ type (
 
 	// ImportOptions are the options passed on to every SchemaImporter.
 	ImportOptions struct {
-		tables         []string
-		excludedTables []string
-		schemaPath     string
-		driver         *mux.ImportDriver
+		tables          []string
+		excludedTables  []string
+		schemaPath      string
+		driver          *mux.ImportDriver
+		baseSchema      string
+		typeNamePrefix  string
+		includedColumns map[string][]string
+		integerPolicy   IntegerPolicy
+		noAnnotations   bool
+		enumGoTypes     map[string]string
+		fileNaming      func(typeName string) string
+		fieldGrouping   bool
+		stubMissingRefs bool
+		entImportPath   string
+		safeIdentifiers bool
+		collectErrors   bool
+		tableAnnots     func(string) []entschema.Annotation
+		immutableCols   map[string]bool
+		postCommand     []string
+		numericAsString bool
+		fieldNames      map[string]string
+		edgeComments    bool
+		int64Columns    map[string]bool
+		noPKStorageKey  bool
+		inferPatterns   []string
+		maxTables       int
+		bytesGoTypes    map[string]string
+		edgeInflector   Inflector
+		atomicWrite     bool
+		joinActions     bool
+		enumColumns     map[string][]string
+		binary16UUID    bool
+		directives      bool
+		autoTimeMixin   bool
+		createdColumns  []string
+		updatedColumns  []string
+		packageName     string
+		header          string
+		comments        map[string]string
+		excludedColumns map[string][]string
+		idPosition      IDPosition
+		validateFKs     bool
+		regexValidators bool
+		polymorphic     bool
+		generateFile    bool
+		schemas         []string
 	}
 
 	// ImportOption allows for managing import configuration using functional options.
 	ImportOption func(*ImportOptions)
+
+	// IntegerPolicy defines how the signedness of integer columns is mapped to ent fields.
+	IntegerPolicy uint
+
+	// IDPosition defines where the id field is placed in the fields of the generated schemas.
+	IDPosition uint
+
+	// Inflector defines how the names of edges are derived from the names of the types they reference.
+	Inflector interface {
+		// Plural returns the name of a non-unique edge to the given type, e.g. "pets" for "Pet".
+		Plural(typeName string) string
+		// Singular returns the name of a unique edge from the name of a non-unique one, e.g. "pet" for "pets".
+		Singular(edgeName string) string
+	}
+
+	// defaultInflector names edges after the tables of their types, using the inflect package.
+	defaultInflector struct{}
+)
+
+const (
+	// Preserve keeps the signedness of integer columns.
+	Preserve IntegerPolicy = iota
+	// Signed maps all integer columns to signed fields.
+	Signed
+	// Unsigned maps all integer columns to unsigned fields.
+	Unsigned
+)
+
+const (
+	// First places the id field before the other fields.
+	First IDPosition = iota
+	// Last places the id field after the other fields.
+	Last
 )
 
 // WithSchemaPath provides a DSN (data source name) for reading the schema & tables from.
@@ -65,6 +167,16 @@ func WithSchemaPath(path string) ImportOption {
 	}
 }
 
+// WithSchemas imports the tables of the given schemas, instead of the schema of the data source (e.g. the first
+// entry of the Postgres search_path). Tables that have the same names as tables of previous schemas are imported
+// as types prefixed with the names of their schemas, e.g. "BillingUser" for the "users" table of the "billing"
+// schema. Supported by Postgres.
+func WithSchemas(schemas []string) ImportOption {
+	return func(i *ImportOptions) {
+		i.schemas = schemas
+	}
+}
+
 // WithTables limits the schema import to a set of given tables (by all tables are imported)
 func WithTables(tables []string) ImportOption {
 	return func(i *ImportOptions) {
@@ -86,303 +198,2186 @@ func WithDriver(drv *mux.ImportDriver) ImportOption {
 	}
 }
 
-// NewImport calls the relevant data source importer based on a given dialect.
-func NewImport(opts ...ImportOption) (SchemaImporter, error) {
-	var (
-		si  SchemaImporter
-		err error
-	)
-	i := &ImportOptions{}
-	for _, apply := range opts {
-		apply(i)
-	}
-	switch i.driver.Dialect {
-	case dialect.MySQL:
-		si, err = NewMySQL(i)
-		if err != nil {
-			return nil, err
-		}
-	case dialect.Postgres:
-		si, err = NewPostgreSQL(i)
-		if err != nil {
-			return nil, err
-		}
-	default:
-		return nil, fmt.Errorf("entimport: unsupported dialect %q", i.driver.Dialect)
+// WithBaseSchema sets the Go type embedded in the generated schemas instead of ent.Schema.
+// The type is given with its full import path, for example: "github.com/org/project/ent/schema/base.Schema".
+func WithBaseSchema(goType string) ImportOption {
+	return func(i *ImportOptions) {
+		i.baseSchema = goType
 	}
-	return si, err
 }
 
-// WriteSchema receives a list of mutators, and writes an ent schema to a given location in the file system.
-func WriteSchema(mutations []schemast.Mutator, opts ...ImportOption) error {
-	i := &ImportOptions{}
-	for _, apply := range opts {
-		apply(i)
+// WithTypeNamePrefix adds the given prefix to the names of the generated types, for example: "DB" for "DBUser".
+// The table annotation of the generated types keeps the original table name.
+func WithTypeNamePrefix(prefix string) ImportOption {
+	return func(i *ImportOptions) {
+		i.typeNamePrefix = prefix
 	}
-	ctx, err := schemast.Load(i.schemaPath)
-	if err != nil {
-		return err
+}
+
+// WithIncludedColumns limits the import of the given tables (keys) to a set of columns (values).
+// The primary key is always imported, and foreign keys of columns that are not included are ignored.
+func WithIncludedColumns(columns map[string][]string) ImportOption {
+	return func(i *ImportOptions) {
+		i.includedColumns = columns
 	}
-	if err = schemast.Mutate(ctx, mutations...); err != nil {
-		return err
+}
+
+// WithExcludedColumns excludes the given columns (values) from the import of their tables (keys). Primary keys
+// and columns of foreign keys cannot be excluded, as their fields and edges are required by the schema.
+func WithExcludedColumns(columns map[string][]string) ImportOption {
+	return func(i *ImportOptions) {
+		i.excludedColumns = columns
 	}
-	return ctx.Print(i.schemaPath, schemast.Header(header))
 }
 
-// entEdge creates an edge based on the given params and direction.
-func entEdge(nodeName, nodeType string, currentNode *schemast.UpsertSchema, dir edgeDir, opts relOptions) (e ent.Edge) {
-	var desc *edge.Descriptor
-	switch dir {
-	case to:
-		e = edge.To(nodeName, ent.Schema.Type)
-		desc = e.Descriptor()
-		if opts.uniqueEdgeToChild {
-			desc.Unique = true
-			desc.Name = inflect.Singularize(nodeName)
-		}
-		if opts.recursive {
-			desc.Name = "child_" + desc.Name
-		}
-	case from:
-		e = edge.From(nodeName, ent.Schema.Type)
-		desc = e.Descriptor()
-		if opts.uniqueEdgeFromParent {
-			desc.Unique = true
-			desc.Name = inflect.Singularize(nodeName)
-		}
-		if opts.edgeField != "" {
-			setEdgeField(e, opts, currentNode)
-		}
-		// RefName describes which entEdge of the Parent Node we're referencing
-		// because there can be multiple references from one node to another.
-		refName := opts.refName
-		if opts.uniqueEdgeToChild {
-			refName = inflect.Singularize(refName)
-		}
-		desc.RefName = refName
-		if opts.recursive {
-			desc.Name = "parent_" + desc.Name
-			desc.RefName = "child_" + desc.RefName
-		}
+// WithIDPosition sets the position of the id field in the generated schemas (First by default).
+func WithIDPosition(pos IDPosition) ImportOption {
+	return func(i *ImportOptions) {
+		i.idPosition = pos
 	}
-	desc.Type = nodeType
-	return e
 }
 
-// setEdgeField is a function to properly name edge fields.
-func setEdgeField(e ent.Edge, opts relOptions, childNode *schemast.UpsertSchema) {
-	edgeField := opts.edgeField
-	// rename the field in case the edge and the field have the same name
-	if e.Descriptor().Name == edgeField {
-		edgeField += "_id"
-		for _, f := range childNode.Fields {
-			if f.Descriptor().Name == opts.edgeField {
-				f.Descriptor().Name = edgeField
-			}
-		}
+// WithIntegerPolicy sets the policy for the signedness of integer fields (Preserve by default).
+// Columns whose signedness is changed by the policy keep their original type as the field SchemaType.
+func WithIntegerPolicy(p IntegerPolicy) ImportOption {
+	return func(i *ImportOptions) {
+		i.integerPolicy = p
 	}
-	e.Descriptor().Field = edgeField
 }
 
-// upsertRelation takes 2 nodes and created the edges between them.
-func upsertRelation(nodeA *schemast.UpsertSchema, nodeB *schemast.UpsertSchema, opts relOptions) {
-	tableA := tableName(nodeA.Name)
-	tableB := tableName(nodeB.Name)
-	fromA := entEdge(tableA, nodeA.Name, nodeB, from, opts)
-	toB := entEdge(tableB, nodeB.Name, nodeA, to, opts)
-	nodeA.Edges = append(nodeA.Edges, toB)
-	nodeB.Edges = append(nodeB.Edges, fromA)
+// WithoutAnnotations omits the Annotations method from the generated schemas, for projects that do not use
+// entsql annotations (for example, when table names are managed in another way).
+func WithoutAnnotations(without bool) ImportOption {
+	return func(i *ImportOptions) {
+		i.noAnnotations = without
+	}
 }
 
-// upsertManyToMany handles the creation of M2M relations.
-func upsertManyToMany(mutations map[string]schemast.Mutator, table *schema.Table) error {
-	tableA := table.ForeignKeys[0].RefTable
-	tableB := table.ForeignKeys[1].RefTable
-	var opts relOptions
-	if tableA.Name == tableB.Name {
-		opts.recursive = true
+// WithEnumGoType sets the Go types of enum columns, given as "table.column" (keys) and the full Go type (values),
+// for example: "github.com/org/project/types.Status". The types must implement field.EnumValues, as the values of
+// the enum are provided by them.
+func WithEnumGoType(types map[string]string) ImportOption {
+	return func(i *ImportOptions) {
+		i.enumGoTypes = types
 	}
-	nodeA, ok := mutations[tableA.Name].(*schemast.UpsertSchema)
-	if !ok {
-		return joinTableErr
+}
+
+// WithBytesGoType sets the Go types of binary columns, given as "table.column" (keys) and the full Go type (values),
+// for example: "github.com/org/project/crypto.Blob". The types must be based on []byte, such as wrappers of
+// encrypted values.
+func WithBytesGoType(types map[string]string) ImportOption {
+	return func(i *ImportOptions) {
+		i.bytesGoTypes = types
 	}
-	nodeB, ok := mutations[tableB.Name].(*schemast.UpsertSchema)
-	if !ok {
-		return joinTableErr
+}
+
+// WithEnumColumns imports the given columns as enum fields, given as "table.column" (keys) and the enum values.
+// Integer columns keep their type in the database (e.g. enums that are stored as integers with a separate mapping),
+// and their values are expected to be converted to integers by the Go type of the enum (see WithEnumGoType).
+func WithEnumColumns(columns map[string][]string) ImportOption {
+	return func(i *ImportOptions) {
+		i.enumColumns = columns
 	}
-	opts.refName = tableName(nodeB.Name)
-	upsertRelation(nodeA, nodeB, opts)
-	return nil
 }
 
-// Note: at this moment ent doesn't support fields on m2m relations.
-func isJoinTable(table *schema.Table) bool {
-	if table.PrimaryKey == nil || len(table.PrimaryKey.Parts) != 2 || len(table.ForeignKeys) != 2 {
-		return false
+// WithFileNaming sets the function that returns the file names of the generated types, for example "User.go"
+// or "user_schema.go" for "User". By default, types are written to their snake-cased names ("user.go").
+// Existing types are kept in their files.
+func WithFileNaming(fn func(typeName string) string) ImportOption {
+	return func(i *ImportOptions) {
+		i.fileNaming = fn
 	}
-	// Make sure that the foreign key columns exactly match primary key column.
-	for _, fk := range table.ForeignKeys {
-		if len(fk.Columns) != 1 {
-			return false
-		}
-		if fk.Columns[0] != table.PrimaryKey.Parts[0].C && fk.Columns[0] != table.PrimaryKey.Parts[1].C {
-			return false
-		}
+}
+
+// WithFieldGrouping orders the fields of the generated schemas by groups: the id field first, then the scalar
+// fields, and the foreign key fields last. The order of the columns is kept within each group.
+func WithFieldGrouping(grouping bool) ImportOption {
+	return func(i *ImportOptions) {
+		i.fieldGrouping = grouping
 	}
-	return true
 }
 
-func typeName(tableName string) string {
-	return inflect.Camelize(inflect.Singularize(tableName))
+// WithStubMissingRefs adds a commented placeholder edge for foreign keys referencing tables that are not imported,
+// instead of dropping the relation silently.
+func WithStubMissingRefs(stub bool) ImportOption {
+	return func(i *ImportOptions) {
+		i.stubMissingRefs = stub
+	}
 }
 
-func tableName(typeName string) string {
-	return inflect.Underscore(inflect.Pluralize(typeName))
+// WithEntImportPath sets the import path of the ent module used by the generated schemas, for custom forks
+// of ent. For example, "github.com/org/ent" results in imports such as "github.com/org/ent/schema/field".
+func WithEntImportPath(path string) ImportOption {
+	return func(i *ImportOptions) {
+		i.entImportPath = path
+	}
 }
 
-// resolvePrimaryKey returns the primary key as an ent field for a given table.
-func resolvePrimaryKey(field fieldFunc, table *schema.Table) (f ent.Field, err error) {
-	if table.PrimaryKey == nil {
-		return nil, fmt.Errorf("entimport: missing primary key (table: %v)", table.Name)
+// WithSafeIdentifiers suffixes the names of fields whose columns are named as Go keywords (e.g. "type" becomes
+// "type_field"), and keeps the column name as their storage key.
+func WithSafeIdentifiers(safe bool) ImportOption {
+	return func(i *ImportOptions) {
+		i.safeIdentifiers = safe
 	}
-	if len(table.PrimaryKey.Parts) != 1 {
-		return nil, fmt.Errorf("entimport: invalid primary key, single part key must be present (table: %v, got: %v parts)", table.Name, len(table.PrimaryKey.Parts))
+}
+
+// WithForeignKeyValidation configures the import to validate the foreign keys before generating the edges, and fail
+// on foreign keys that reference tables that are not imported (unless stubbed by WithStubMissingRefs), columns other
+// than the primary key, or a primary key of an incompatible type. All invalid foreign keys are reported together.
+func WithForeignKeyValidation(validate bool) ImportOption {
+	return func(i *ImportOptions) {
+		i.validateFKs = validate
 	}
-	if f, err = field(table.PrimaryKey.Parts[0].C); err != nil {
-		return nil, err
+}
+
+// WithRegexValidators adds a Match validator to string fields whose columns have a regular expression CHECK
+// constraint, e.g. CHECK (code ~ '^[A-Z]{3}$') in Postgres. Case-insensitive matches (~*) are supported, and
+// expressions that cannot be compiled by the regexp package are skipped.
+func WithRegexValidators(emit bool) ImportOption {
+	return func(i *ImportOptions) {
+		i.regexValidators = emit
 	}
-	if d := f.Descriptor(); d.Name != "id" {
-		d.StorageKey = d.Name
-		d.Name = "id"
+}
+
+// WithPolymorphicHints adds a comment to the edges of types whose tables have polymorphic associations, i.e. pairs
+// of <name>_type and <name>_id columns (e.g. commentable_type and commentable_id), as they cannot be foreign keys
+// and are not imported as edges.
+func WithPolymorphicHints(hint bool) ImportOption {
+	return func(i *ImportOptions) {
+		i.polymorphic = hint
 	}
-	return f, nil
 }
 
-// upsertNode handles the creation of a node from a given table.
-func upsertNode(field fieldFunc, table *schema.Table) (*schemast.UpsertSchema, error) {
-	upsert := &schemast.UpsertSchema{
-		Name: typeName(table.Name),
+// WithGenerateFile writes a generate.go file with the go:generate directive of the ent codegen into the parent
+// directory of the schema path (e.g. "ent" for "ent/schema"), unless the file already exists.
+func WithGenerateFile(emit bool) ImportOption {
+	return func(i *ImportOptions) {
+		i.generateFile = emit
 	}
-	if tableName(table.Name) != table.Name {
-		upsert.Annotations = []entschema.Annotation{
-			entsql.Annotation{Table: table.Name},
-		}
+}
+
+// WithCollectErrors configures the import to process all tables in case of errors, instead of failing on the
+// first one. The errors of all tables are returned together at the end of the import.
+func WithCollectErrors(collect bool) ImportOption {
+	return func(i *ImportOptions) {
+		i.collectErrors = collect
 	}
-	fields := make(map[string]ent.Field, len(upsert.Fields))
-	for _, f := range upsert.Fields {
-		fields[f.Descriptor().StorageKey] = f
+}
+
+// WithTableAnnotations sets a function that returns the annotations added to the schema of each imported table,
+// for example, tenant or module metadata used by downstream code generation. Note that the annotations must be
+// supported by schemast (e.g. entsql.Annotation) in order to be written by WriteSchema.
+func WithTableAnnotations(fn func(table string) []entschema.Annotation) ImportOption {
+	return func(i *ImportOptions) {
+		i.tableAnnots = fn
 	}
-	pk, err := resolvePrimaryKey(field, table)
-	if err != nil {
-		return nil, err
+}
+
+// WithImmutableColumns marks the fields of the given columns (e.g. "created_at") as immutable in all tables.
+// Foreign key columns are not marked, as ent does not support immutable edge fields.
+func WithImmutableColumns(columns []string) ImportOption {
+	return func(i *ImportOptions) {
+		i.immutableCols = make(map[string]bool, len(columns))
+		for _, c := range columns {
+			i.immutableCols[c] = true
+		}
 	}
-	if _, ok := fields[pk.Descriptor().StorageKey]; !ok {
-		fields[pk.Descriptor().StorageKey] = pk
-		upsert.Fields = append(upsert.Fields, pk)
+}
+
+// WithPostCommand sets a command that is executed on the schema directory after it is written by WriteSchema,
+// for example: []string{"gofumpt", "-w"}. The path of the directory is passed as the last argument.
+func WithPostCommand(command []string) ImportOption {
+	return func(i *ImportOptions) {
+		i.postCommand = command
 	}
-	for _, column := range table.Columns {
-		if table.PrimaryKey != nil &&
-			len(table.PrimaryKey.Parts) != 0 &&
-			table.PrimaryKey.Parts[0].C.Name == column.Name {
-			continue
-		}
-		fld, err := field(column)
-		if err != nil {
-			return nil, err
-		}
-		if _, ok := fields[column.Name]; !ok {
-			fields[column.Name] = fld
-			upsert.Fields = append(upsert.Fields, fld)
-		}
+}
+
+// WithPackageName sets the name of the package declared by the generated schema files, in case it is not "schema".
+func WithPackageName(name string) ImportOption {
+	return func(i *ImportOptions) {
+		i.packageName = name
 	}
-	for _, index := range table.Indexes {
-		if index.Unique && len(index.Parts) == 1 {
-			fields[index.Parts[0].C.Name].Descriptor().Unique = true
-		}
+}
+
+// WithHeader sets the comment written at the top of the schema files, replacing the default "Code generated by
+// entimport, DO NOT EDIT." comment. Multi-line comments are supported, for example:
+// "Code generated by entimport, DO NOT EDIT.\nSource: https://github.com/org/project/blob/master/schema.sql".
+func WithHeader(text string) ImportOption {
+	return func(i *ImportOptions) {
+		i.header = text
 	}
-	for _, fk := range table.ForeignKeys {
-		for _, column := range fk.Columns {
-			// FK / Reference column
-			fld, ok := fields[column.Name]
-			if !ok {
-				return nil, fmt.Errorf("foreign key for column: %q doesn't exist in referenced table", column.Name)
-			}
-			fld.Descriptor().Optional = true
-		}
+}
+
+// WithUnboundedNumericAsString maps Postgres numeric columns without precision to string fields, as their values
+// may exceed the range and the precision of float64. By default, they are mapped to float64 fields.
+func WithUnboundedNumericAsString(asString bool) ImportOption {
+	return func(i *ImportOptions) {
+		i.numericAsString = asString
 	}
-	return upsert, err
 }
 
-// applyColumnAttributes adds column attributes to a given ent field.
-func applyColumnAttributes(f ent.Field, col *schema.Column) {
-	desc := f.Descriptor()
-	desc.Optional = col.Type.Null
-	for _, attr := range col.Attrs {
-		if a, ok := attr.(*schema.Comment); ok {
-			desc.Comment = a.Text
-		}
+// WithBinary16AsUUID maps MySQL binary(16) columns to UUID fields, as UUIDs are commonly stored in their binary
+// form. The column type is kept as the field SchemaType. By default, they are mapped to bytes fields.
+func WithBinary16AsUUID(asUUID bool) ImportOption {
+	return func(i *ImportOptions) {
+		i.binary16UUID = asUUID
 	}
 }
 
-// schemaMutations is in charge of creating all the schema mutations needed for an ent schema.
-func schemaMutations(field fieldFunc, tables []*schema.Table) ([]schemast.Mutator, error) {
-	mutations := make(map[string]schemast.Mutator)
-	joinTables := make(map[string]*schema.Table)
-	for _, table := range tables {
-		if isJoinTable(table) {
-			joinTables[table.Name] = table
-			continue
-		}
-		node, err := upsertNode(field, table)
-		if err != nil {
-			return nil, fmt.Errorf("entimport: issue with table %v: %w", table.Name, err)
-		}
-		mutations[table.Name] = node
+// WithCommentDirectives configures if the cardinality of the relations is read from directives in the comments of
+// their foreign key columns, overriding the cardinality inferred from the unique indexes: "@o2o" for one-to-one
+// relations and "@o2m" for one-to-many relations. For example: "the account of the user @o2o".
+func WithCommentDirectives(parse bool) ImportOption {
+	return func(i *ImportOptions) {
+		i.directives = parse
 	}
-	for _, table := range tables {
-		if t, ok := joinTables[table.Name]; ok {
-			err := upsertManyToMany(mutations, t)
-			if err != nil {
-				return nil, err
-			}
-			continue
-		}
-		upsertOneToX(mutations, table)
+}
+
+// WithAutoTimeMixin configures if the created_at and updated_at time fields that are shared by multiple types are
+// extracted into a generated TimeMixin, which is used by these types instead of declaring the fields.
+func WithAutoTimeMixin(extract bool) ImportOption {
+	return func(i *ImportOptions) {
+		i.autoTimeMixin = extract
 	}
-	ml := make([]schemast.Mutator, 0, len(mutations))
-	for _, mutator := range mutations {
-		ml = append(ml, mutator)
+}
+
+// WithTimeDefaults sets time.Now as the default value of the time fields of the audit columns matching the given
+// patterns (see path.Match), for example: "created_at" or "*_created". The fields of updated columns are also updated
+// to time.Now on every update (UpdateDefault).
+func WithTimeDefaults(created, updated []string) ImportOption {
+	return func(i *ImportOptions) {
+		i.createdColumns = created
+		i.updatedColumns = updated
 	}
-	return ml, nil
 }
 
-// O2O Two Types - Child Table has a unique reference (FK) to Parent table
+// WithCommentSource sets the comments of the fields of the given columns, given as "table.column" (keys), for
+// example from a data dictionary maintained outside the database. Comments of the columns in the database take
+// precedence over the given comments.
+func WithCommentSource(comments map[string]string) ImportOption {
+	return func(i *ImportOptions) {
+		i.comments = comments
+	}
+}
+
+// WithFieldNameMap sets the names of the fields of the given columns, given as "table.column" (keys), for example:
+// "users.usr_nm": "username". The fields keep the column names as their storage keys.
+func WithFieldNameMap(names map[string]string) ImportOption {
+	return func(i *ImportOptions) {
+		i.fieldNames = names
+	}
+}
+
+// WithEdgeComments adds a comment to the generated edges, describing the foreign key or the join table they are
+// derived from, for tracing the inferred relations back to the database constraints.
+func WithEdgeComments(comments bool) ImportOption {
+	return func(i *ImportOptions) {
+		i.edgeComments = comments
+	}
+}
+
+// WithInt64Columns maps the given 64-bit integer columns to explicit field.Int64 fields, instead of field.Int
+// fields (platform-dependent size). Unsigned columns are mapped to field.Uint64 regardless of this option, and
+// the signedness of the columns follows the integer policy.
+func WithInt64Columns(columns []string) ImportOption {
+	return func(i *ImportOptions) {
+		i.int64Columns = make(map[string]bool, len(columns))
+		for _, c := range columns {
+			i.int64Columns[c] = true
+		}
+	}
+}
+
+// WithPKStorageKey configures if the column name of primary keys that are not named "id" is kept as the storage key
+// of their "id" field. Defaults to true.
+func WithPKStorageKey(emit bool) ImportOption {
+	return func(i *ImportOptions) {
+		i.noPKStorageKey = !emit
+	}
+}
+
+// WithInferredEdges configures the import to create edges for columns that reference other tables without foreign
+// key constraints (e.g. in PlanetScale databases), by the given column name patterns. A pattern holds a "{table}"
+// placeholder for the name of the referenced table, for example: "{table}_id" matches a "status_id" column
+// referencing the primary key of the "statuses" lookup table.
+func WithInferredEdges(patterns ...string) ImportOption {
+	return func(i *ImportOptions) {
+		i.inferPatterns = patterns
+	}
+}
+
+// WithMaxTables limits the number of tables to import, after applying the table filters, as a safety valve
+// against importing a large database by accident. The import fails if the limit is exceeded. Disabled if 0.
+func WithMaxTables(n int) ImportOption {
+	return func(i *ImportOptions) {
+		i.maxTables = n
+	}
+}
+
+// WithEdgeInflector sets the inflector of the edge names, independently of the type names. By default, edges
+// are named after the (pluralized) tables of the types they reference, and singularized if they are unique.
+func WithEdgeInflector(inflector Inflector) ImportOption {
+	return func(i *ImportOptions) {
+		i.edgeInflector = inflector
+	}
+}
+
+// WithAtomicWrite configures WriteSchema to write the schema into a copy of the schema directory, and to replace
+// the directory with it only if the write (including the post command) succeeds. On failure, the schema directory
+// is left untouched. Note that the post command is executed on the copy of the directory, and that the schema
+// directory is missing for a short moment while the directories are swapped.
+func WithAtomicWrite(atomic bool) ImportOption {
+	return func(i *ImportOptions) {
+		i.atomicWrite = atomic
+	}
+}
+
+// WithJoinTableActions adds the ON DELETE action of the foreign keys of M2M join tables to their edges, as an
+// entsql annotation. The action is added only if both foreign keys have the same (non-default) action.
+func WithJoinTableActions(emit bool) ImportOption {
+	return func(i *ImportOptions) {
+		i.joinActions = emit
+	}
+}
+
+// NewImport calls the relevant data source importer based on a given dialect.
+func NewImport(opts ...ImportOption) (SchemaImporter, error) {
+	var (
+		si  SchemaImporter
+		err error
+	)
+	i := &ImportOptions{}
+	for _, apply := range opts {
+		apply(i)
+	}
+	switch i.driver.Dialect {
+	case dialect.MySQL:
+		si, err = NewMySQL(i)
+		if err != nil {
+			return nil, err
+		}
+	case dialect.Postgres:
+		si, err = NewPostgreSQL(i)
+		if err != nil {
+			return nil, err
+		}
+	case dialect.SQLite:
+		si, err = NewSQLite(i)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		si, err = NewCustom(i)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return si, err
+}
+
+// includeColumn reports if the given column should be imported as part of its table.
+func (i *ImportOptions) includeColumn(table, column string) bool {
+	for _, c := range i.excludedColumns[table] {
+		if c == column {
+			return false
+		}
+	}
+	columns, ok := i.includedColumns[table]
+	if !ok {
+		return true
+	}
+	for _, c := range columns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+// checkExcludedColumns returns an error if an excluded column of the table is part of its primary key or one
+// of its foreign keys, instead of importing a schema with a missing id or a dangling edge.
+func (i *ImportOptions) checkExcludedColumns(table *schema.Table) error {
+	for _, name := range i.excludedColumns[table.Name] {
+		column, ok := table.Column(name)
+		if !ok {
+			continue
+		}
+		if isPrimaryKey(table, column) || isWeakEntity(table) && isPrimaryKeyPart(table, column) {
+			return fmt.Errorf("column %q cannot be excluded, as it is part of the primary key", name)
+		}
+		for _, fk := range table.ForeignKeys {
+			for _, c := range fk.Columns {
+				if c.Name == name {
+					return fmt.Errorf("column %q cannot be excluded, as it is part of the foreign key %q referencing table %q", name, fk.Symbol, fk.RefTable.Name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// enumColumn returns an enum field for the column, in case it was configured by WithEnumColumns.
+func (i *ImportOptions) enumColumn(table string, column *schema.Column, f ent.Field) ent.Field {
+	values, ok := i.enumColumns[table+"."+column.Name]
+	if !ok {
+		return f
+	}
+	desc := f.Descriptor()
+	e := enumField(desc.Name, values)
+	switch t := desc.Info.Type; {
+	case t == field.TypeString || t == field.TypeEnum:
+	case t.Integer():
+		e.Descriptor().SchemaType = map[string]string{
+			i.driver.Dialect: column.Type.Raw, // Override the dialect.
+		}
+	default:
+		return f
+	}
+	applyColumnAttributes(e, column)
+	return e
+}
+
+// enumGoType sets the Go type of an enum field, in case it was configured for its column.
+// The values of the enum are dropped, because ent takes them from the Go type.
+func (i *ImportOptions) enumGoType(table string, f ent.Field) {
+	desc := f.Descriptor()
+	goType, ok := i.enumGoTypes[table+"."+desc.Name]
+	if !ok || desc.Info.Type != field.TypeEnum {
+		return
+	}
+	desc.Enums = nil
+	desc.Annotations = append(desc.Annotations, goTypeCall(goType, `("")`))
+}
+
+// bytesGoType sets the Go type of a bytes field, in case it was configured for its column.
+func (i *ImportOptions) bytesGoType(table string, f ent.Field) {
+	desc := f.Descriptor()
+	goType, ok := i.bytesGoTypes[table+"."+desc.Name]
+	if !ok || desc.Info.Type != field.TypeBytes {
+		return
+	}
+	desc.Annotations = append(desc.Annotations, goTypeCall(goType, "{}"))
+}
+
+// goTypeCall returns the GoType call of the given full Go type, with the suffix that makes its value.
+func goTypeCall(goType, value string) *callAnnotation {
+	call := &callAnnotation{Method: "GoType", Args: []string{goType + value}}
+	if idx := strings.LastIndex(goType, "."); idx != -1 {
+		pkgPath := goType[:idx]
+		call.Args = []string{path.Base(pkgPath) + goType[idx:] + value}
+		call.Imports = []string{pkgPath}
+	}
+	return call
+}
+
+// integerField returns an integer field of the given size in bits, with the signedness set by the integer policy.
+// In case the policy changes the signedness of the column, its type is kept as the field SchemaType.
+func (i *ImportOptions) integerField(name string, bits int, unsigned bool, dialect, colType string) (f ent.Field) {
+	fieldUnsigned := unsigned
+	switch i.integerPolicy {
+	case Signed:
+		fieldUnsigned = false
+	case Unsigned:
+		fieldUnsigned = true
+	}
+	switch bits {
+	case 8:
+		if f = field.Int8(name); fieldUnsigned {
+			f = field.Uint8(name)
+		}
+	case 16:
+		if f = field.Int16(name); fieldUnsigned {
+			f = field.Uint16(name)
+		}
+	case 32:
+		if f = field.Int32(name); fieldUnsigned {
+			f = field.Uint32(name)
+		}
+	case 64:
+		// Int64 is not used on purpose, unless it is configured for the column.
+		if f = field.Int(name); fieldUnsigned {
+			f = field.Uint64(name)
+		} else if i.int64Columns[name] {
+			f = field.Int64(name)
+		}
+	default:
+		return nil
+	}
+	if fieldUnsigned != unsigned {
+		f.Descriptor().SchemaType = map[string]string{dialect: colType}
+	}
+	return f
+}
+
+// WriteSchema receives a list of mutators, and writes an ent schema to a given location in the file system.
+// It returns the sorted paths of the files holding the types of the mutators.
+func WriteSchema(mutations []schemast.Mutator, opts ...ImportOption) ([]string, error) {
+	i := &ImportOptions{}
+	for _, apply := range opts {
+		apply(i)
+	}
+	mutations = withMixins(mutations)
+	write := writeSchema
+	if i.atomicWrite {
+		write = writeAtomic
+	}
+	if err := write(i, mutations); err != nil {
+		return nil, err
+	}
+	if i.generateFile {
+		if err := writeGenerateFile(i); err != nil {
+			return nil, err
+		}
+	}
+	return writtenFiles(i.schemaPath, mutations)
+}
+
+// writeGenerateFile writes the generate.go file of the ent codegen into the parent directory of the schema path,
+// unless it already exists. The file is declared in a package named after the directory, or "ent" in case the
+// name of the directory is not a valid identifier.
+func writeGenerateFile(i *ImportOptions) error {
+	schemaPath := filepath.Clean(i.schemaPath)
+	dir := filepath.Dir(schemaPath)
+	path := filepath.Join(dir, "generate.go")
+	switch _, err := os.Stat(path); {
+	case err == nil:
+		return nil
+	case !errors.Is(err, os.ErrNotExist):
+		return err
+	}
+	pkg := filepath.Base(dir)
+	if abs, err := filepath.Abs(dir); err == nil {
+		pkg = filepath.Base(abs)
+	}
+	if !token.IsIdentifier(pkg) {
+		pkg = "ent"
+	}
+	importPath := entImportPath
+	if i.entImportPath != "" {
+		importPath = i.entImportPath
+	}
+	src := fmt.Sprintf("package %s\n\n//go:generate go run %s/cmd/ent generate ./%s\n", pkg, importPath, filepath.ToSlash(filepath.Base(schemaPath)))
+	if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+		return fmt.Errorf("entimport: write generate file: %w", err)
+	}
+	return nil
+}
+
+// RenderSchema renders the ent schema of the given mutators without writing it to the schema directory, and returns
+// the files of the imported types by their names. The schema is rendered over a temporary copy of the directory
+// given by WithSchemaPath (if it exists) like WriteSchema does, and the copy is removed afterwards.
+func RenderSchema(mutations []schemast.Mutator, opts ...ImportOption) (map[string][]byte, error) {
+	i := &ImportOptions{}
+	for _, apply := range opts {
+		apply(i)
+	}
+	src := filepath.Clean(i.schemaPath)
+	// The copy is created next to the schema directory, as the schema package is loaded from its module.
+	parent, pattern := filepath.Dir(src), "."+filepath.Base(src)+"-*"
+	if _, err := os.Stat(src); i.schemaPath == "" || err != nil {
+		parent, pattern = "", "entimport-*"
+	}
+	tmp, err := os.MkdirTemp(parent, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("entimport: create temporary schema directory: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+	// The schema is written to a subdirectory, as files may be written to its parent (see WithGenerateFile).
+	dir := filepath.Join(tmp, "schema")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		return nil, err
+	}
+	if parent != "" {
+		if err := copyDir(src, dir); err != nil {
+			return nil, fmt.Errorf("entimport: copy schema directory: %w", err)
+		}
+	}
+	paths, err := WriteSchema(mutations, append(opts, WithSchemaPath(dir))...)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string][]byte, len(paths))
+	for _, p := range paths {
+		buf, err := os.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		files[filepath.Base(p)] = buf
+	}
+	return files, nil
+}
+
+// withMixins returns the given mutations along with the mutations of the mixins used by their types.
+func withMixins(mutations []schemast.Mutator) []schemast.Mutator {
+	seen := make(map[string]bool)
+	for _, m := range mutations {
+		u, ok := m.(*schemast.UpsertSchema)
+		if !ok {
+			continue
+		}
+		for _, a := range u.Annotations {
+			if a, ok := a.(*mixinAnnotation); ok && !seen[a.Type] {
+				seen[a.Type] = true
+				// The list is copied, as the caller may append to it.
+				mutations = append(mutations[:len(mutations):len(mutations)], &schemast.UpsertSchema{Name: a.Type, Fields: a.Fields})
+			}
+		}
+	}
+	return mutations
+}
+
+// writtenFiles returns the sorted paths of the files in the schema directory that declare the types of the
+// given mutations.
+func writtenFiles(dir string, mutations []schemast.Mutator) ([]string, error) {
+	names := make(map[string]bool, len(mutations))
+	for _, m := range mutations {
+		if u, ok := m.(*schemast.UpsertSchema); ok {
+			names[u.Name] = true
+		}
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, p := range paths {
+		f, err := parser.ParseFile(token.NewFileSet(), p, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		if declaresType(f, names) {
+			files = append(files, p)
+		}
+	}
+	return files, nil
+}
+
+// declaresType reports if the file declares one of the given types.
+func declaresType(f *ast.File, names map[string]bool) bool {
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			if ts, ok := spec.(*ast.TypeSpec); ok && names[ts.Name.Name] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// writeAtomic writes the schema into a temporary copy of the schema directory, and swaps the directories
+// in case the write succeeds. The swap takes two renames, and the schema directory does not exist in between.
+// Readers never see a partially written schema, but may fail to find the directory during the swap.
+func writeAtomic(i *ImportOptions, mutations []schemast.Mutator) (err error) {
+	dir := filepath.Clean(i.schemaPath)
+	// The copy is created next to the schema directory, as renames across file systems are not atomic.
+	tmp, err := os.MkdirTemp(filepath.Dir(dir), "."+filepath.Base(dir)+"-*")
+	if err != nil {
+		return fmt.Errorf("entimport: create temporary schema directory: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			os.RemoveAll(tmp)
+		}
+	}()
+	// The copy keeps the permissions of the schema directory, and new directories are created with 0755
+	// instead of the 0700 permissions of os.MkdirTemp.
+	switch err = copyDir(dir, tmp); {
+	case errors.Is(err, os.ErrNotExist):
+		if err = os.Chmod(tmp, 0755); err != nil {
+			return fmt.Errorf("entimport: create temporary schema directory: %w", err)
+		}
+	case err != nil:
+		return fmt.Errorf("entimport: copy schema directory: %w", err)
+	}
+	c := *i
+	c.schemaPath = tmp
+	if err = writeSchema(&c, mutations); err != nil {
+		return err
+	}
+	backup := tmp + ".old"
+	if err = os.Rename(dir, backup); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("entimport: move schema directory: %w", err)
+	}
+	if err = os.Rename(tmp, dir); err != nil {
+		// Restore the original directory, if it exists.
+		os.Rename(backup, dir)
+		return fmt.Errorf("entimport: move schema directory: %w", err)
+	}
+	return os.RemoveAll(backup)
+}
+
+// copyDir copies the files of the src directory (and its subdirectories) to the existing dst directory,
+// keeping their permissions.
+func copyDir(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		switch {
+		case d.IsDir():
+			if rel == "." {
+				return os.Chmod(dst, info.Mode().Perm())
+			}
+			return os.Mkdir(target, info.Mode().Perm())
+		case info.Mode().IsRegular():
+			buf, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			return os.WriteFile(target, buf, info.Mode().Perm())
+		}
+		return nil
+	})
+}
+
+// writeSchema writes the schema mutations into the schema directory.
+func writeSchema(i *ImportOptions, mutations []schemast.Mutator) error {
+	ctx, err := schemast.Load(i.schemaPath)
+	if err != nil {
+		return err
+	}
+	var newTypes []string
+	for _, m := range mutations {
+		if u, ok := m.(*schemast.UpsertSchema); ok && !ctx.HasType(u.Name) {
+			newTypes = append(newTypes, u.Name)
+		}
+	}
+	types, restore := extractDirectives(mutations)
+	defer restore()
+	if err = schemast.Mutate(ctx, mutations...); err != nil {
+		return err
+	}
+	existing, err := filepath.Glob(filepath.Join(i.schemaPath, "*.go"))
+	if err != nil {
+		return err
+	}
+	if err = ctx.Print(i.schemaPath); err != nil {
+		return err
+	}
+	if err = chmodNewFiles(i.schemaPath, existing); err != nil {
+		return err
+	}
+	rewrites := []rewriteFunc{fixTypeArgs}
+	if i.packageName != "" && i.packageName != "schema" {
+		rewrites = append(rewrites, renamePackage(i.packageName))
+	}
+	if len(types) > 0 {
+		rewrites = append(rewrites, applyDirectives(types))
+	}
+	mixins := make(map[string]bool)
+	for _, d := range types {
+		for _, m := range d.mixins {
+			mixins[m] = true
+		}
+	}
+	// Mixins are rewritten before embedding the base schema, which is not embedded in mixins.
+	if len(mixins) > 0 {
+		rewrites = append(rewrites, asMixin(mixins))
+	}
+	if i.baseSchema != "" {
+		rewrites = append(rewrites, embedBaseSchema(i.baseSchema))
+	}
+	if i.entImportPath != "" && i.entImportPath != entImportPath {
+		rewrites = append(rewrites, replaceImportPath(entImportPath, i.entImportPath))
+	}
+	if i.noAnnotations {
+		rewrites = append(rewrites, removeMethod("Annotations"))
+	}
+	if err = rewriteSchema(i.schemaPath, mutations, rewrites...); err != nil {
+		return err
+	}
+	text := header
+	if i.header != "" {
+		text = i.header
+	}
+	if err = addHeader(i.schemaPath, text); err != nil {
+		return err
+	}
+	// New types are printed by schemast to their default file names.
+	for _, name := range newTypes {
+		if i.fileNaming == nil {
+			break
+		}
+		from := filepath.Join(i.schemaPath, inflect.Underscore(name)+".go")
+		to := filepath.Join(i.schemaPath, i.fileNaming(name))
+		if from == to {
+			continue
+		}
+		if err := os.Rename(from, to); err != nil {
+			return fmt.Errorf("entimport: rename file of type %s: %w", name, err)
+		}
+	}
+	if len(i.postCommand) == 0 {
+		return nil
+	}
+	args := append(append([]string(nil), i.postCommand[1:]...), i.schemaPath)
+	if out, err := exec.Command(i.postCommand[0], args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("entimport: post command %q failed: %w: %s", strings.Join(i.postCommand, " "), err, bytes.TrimSpace(out))
+	}
+	return nil
+}
+
+// chmodNewFiles sets the permissions of the files that were added to the schema directory to 0644, as schemast
+// creates them with 0600. The existing files keep their permissions.
+func chmodNewFiles(dir string, existing []string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return err
+	}
+	skip := make(map[string]bool, len(existing))
+	for _, fn := range existing {
+		skip[fn] = true
+	}
+	for _, fn := range files {
+		if skip[fn] {
+			continue
+		}
+		if err := os.Chmod(fn, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addHeader adds the given comment to the top of the schema files, unless they already contain it.
+func addHeader(dir, text string) error {
+	comment := "// " + strings.ReplaceAll(strings.TrimSpace(text), "\n", "\n// ")
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return err
+	}
+	for _, fn := range files {
+		buf, err := os.ReadFile(fn)
+		if err != nil {
+			return err
+		}
+		if len(buf) == 0 || strings.Contains("\n"+string(buf), "\n"+comment+"\n") {
+			continue
+		}
+		if err := os.WriteFile(fn, append([]byte(comment+"\n\n"), buf...), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// entEdge creates an edge based on the given params and direction.
+func entEdge(i *ImportOptions, nodeName, nodeType string, currentNode *schemast.UpsertSchema, dir edgeDir, opts relOptions) (e ent.Edge) {
+	var desc *edge.Descriptor
+	switch dir {
+	case to:
+		e = edge.To(nodeName, ent.Schema.Type)
+		desc = e.Descriptor()
+		if opts.uniqueEdgeToChild {
+			desc.Unique = true
+			desc.Name = i.inflector().Singular(nodeName)
+		}
+		if opts.recursive {
+			desc.Name = "child_" + desc.Name
+		}
+	case from:
+		e = edge.From(nodeName, ent.Schema.Type)
+		desc = e.Descriptor()
+		if opts.uniqueEdgeFromParent {
+			desc.Unique = true
+			desc.Name = i.inflector().Singular(nodeName)
+		}
+		desc.Field = opts.edgeField
+		// RefName describes which entEdge of the Parent Node we're referencing
+		// because there can be multiple references from one node to another.
+		refName := opts.refName
+		if opts.uniqueEdgeToChild {
+			refName = i.inflector().Singular(refName)
+		}
+		desc.RefName = refName
+		if opts.recursive {
+			desc.Name = "parent_" + desc.Name
+			desc.RefName = "child_" + desc.RefName
+		}
+	}
+	desc.Type = nodeType
+	return e
+}
+
+// setEdgeField renames the edge field in case the edge and the field have the same name (e.g. a "user" column
+// referencing the users table), as ent requires the names of the fields and edges of a schema to be unique.
+// The field is looked up by its column, and keeps it as its storage key.
+func setEdgeField(e ent.Edge, opts relOptions, childNode *schemast.UpsertSchema) {
+	desc := e.Descriptor()
+	fld, ok := lookupField(childNode, opts.edgeColumn)
+	if !ok || fld.Descriptor().Name != desc.Name {
+		return
+	}
+	taken := make(map[string]bool, len(childNode.Fields)+len(childNode.Edges))
+	for _, f := range childNode.Fields {
+		taken[f.Descriptor().Name] = true
+	}
+	for _, e := range childNode.Edges {
+		taken[e.Descriptor().Name] = true
+	}
+	name := desc.Name + "_id"
+	for i := 2; taken[name]; i++ {
+		name = fmt.Sprintf("%s_id%d", desc.Name, i)
+	}
+	fd := fld.Descriptor()
+	if fd.StorageKey == "" {
+		fd.StorageKey = fd.Name
+	}
+	fd.Name = name
+	desc.Field = name
+}
+
+// upsertRelation takes 2 nodes and created the edges between them.
+func upsertRelation(i *ImportOptions, nodeA *schemast.UpsertSchema, nodeB *schemast.UpsertSchema, opts relOptions) {
+	// Edges are named by the inflector after the type names, without their prefix.
+	tableA := i.inflector().Plural(strings.TrimPrefix(nodeA.Name, i.typeNamePrefix))
+	tableB := i.inflector().Plural(strings.TrimPrefix(nodeB.Name, i.typeNamePrefix))
+	fromA := entEdge(i, tableA, nodeA.Name, nodeB, from, opts)
+	toB := entEdge(i, tableB, nodeB.Name, nodeA, to, opts)
+	// A table with several foreign keys (e.g. a self-reference and a reference to another table)
+	// may result in edges with the same name, which are disambiguated by their foreign key column.
+	var toNames, fromNames []string
+	if column := strings.TrimSuffix(opts.edgeField, "_id"); column != "" {
+		toNames = append(toNames, column+"_"+toB.Descriptor().Name)
+		if column != opts.edgeField {
+			fromNames = append(fromNames, column)
+		}
+		fromNames = append(fromNames, column+"_"+fromA.Descriptor().Name)
+	}
+	if name := uniqueEdgeName(nodeA, toB.Descriptor().Name, toNames...); name != toB.Descriptor().Name {
+		toB.Descriptor().Name = name
+		fromA.Descriptor().RefName = name
+	}
+	// The referential action of M2M relations is set on the edge that owns the join table.
+	if opts.onDelete != "" && !i.noAnnotations {
+		desc := toB.Descriptor()
+		desc.Annotations = append(desc.Annotations, entsql.Annotation{OnDelete: opts.onDelete})
+	}
+	nodeA.Edges = append(nodeA.Edges, toB)
+	fromA.Descriptor().Name = uniqueEdgeName(nodeB, fromA.Descriptor().Name, fromNames...)
+	// The foreign key field is always set on the inverse edge, as it is added to the node owning the
+	// column (also in self-references, where both edges are added to the same node).
+	if fromA.Descriptor().Field != "" {
+		setEdgeField(fromA, opts, nodeB)
+	}
+	if opts.comment != "" {
+		desc := fromA.Descriptor()
+		desc.Annotations = append(desc.Annotations, &callAnnotation{
+			Method: "Comment",
+			Args:   []string{strconv.Quote(opts.comment)},
+		})
+	}
+	if i.edgeComments && opts.source != "" {
+		for _, e := range []ent.Edge{toB, fromA} {
+			desc := e.Descriptor()
+			desc.Annotations = append(desc.Annotations, &commentAnnotation{
+				Text: fmt.Sprintf("%s: derived from %s", desc.Name, opts.source),
+			})
+		}
+	}
+	nodeB.Edges = append(nodeB.Edges, fromA)
+}
+
+// uniqueEdgeName returns the given name if the node has no edge with this name. Otherwise, the first
+// alternative that is not used is returned, or the name suffixed with a number if all are used.
+func uniqueEdgeName(node *schemast.UpsertSchema, name string, alternatives ...string) string {
+	taken := make(map[string]bool, len(node.Edges))
+	for _, e := range node.Edges {
+		taken[e.Descriptor().Name] = true
+	}
+	if !taken[name] {
+		return name
+	}
+	for _, n := range alternatives {
+		if !taken[n] {
+			return n
+		}
+	}
+	for i := 2; ; i++ {
+		if n := fmt.Sprintf("%s%d", name, i); !taken[n] {
+			return n
+		}
+	}
+}
+
+// upsertManyToMany handles the creation of M2M relations.
+func upsertManyToMany(i *ImportOptions, mutations map[string]schemast.Mutator, table *schema.Table) error {
+	if err := i.checkExcludedColumns(table); err != nil {
+		return fmt.Errorf("entimport: issue with table %v: %w", table.Name, err)
+	}
+	tableA := table.ForeignKeys[0].RefTable
+	tableB := table.ForeignKeys[1].RefTable
+	var opts relOptions
+	if tableA.Name == tableB.Name {
+		opts.recursive = true
+	}
+	nodeA, ok := mutations[tableA.Name].(*schemast.UpsertSchema)
+	if !ok {
+		return joinTableErr
+	}
+	nodeB, ok := mutations[tableB.Name].(*schemast.UpsertSchema)
+	if !ok {
+		return joinTableErr
+	}
+	opts.refName = i.inflector().Plural(strings.TrimPrefix(nodeB.Name, i.typeNamePrefix))
+	opts.source = fmt.Sprintf("join table %s", table.Name)
+	if i.joinActions {
+		opts.onDelete = joinTableAction(table)
+	}
+	upsertRelation(i, nodeA, nodeB, opts)
+	return nil
+}
+
+// joinTableAction returns the ON DELETE action of the foreign keys of the join table, in case both foreign keys
+// have the same action, and it is not the default one.
+func joinTableAction(table *schema.Table) entsql.ReferenceOption {
+	a1 := entsql.ReferenceOption(strings.ToUpper(string(table.ForeignKeys[0].OnDelete)))
+	a2 := entsql.ReferenceOption(strings.ToUpper(string(table.ForeignKeys[1].OnDelete)))
+	if a1 != a2 || a1 == entsql.NoAction {
+		return ""
+	}
+	return a1
+}
+
+// isPrimaryKey reports if the column is the (first) primary key column of the table, which is imported as the id field.
+func isPrimaryKey(table *schema.Table, column *schema.Column) bool {
+	pk := table.PrimaryKey
+	return pk != nil && len(pk.Parts) != 0 && pk.Parts[0].C != nil && pk.Parts[0].C.Name == column.Name
+}
+
+// isPrimaryKeyPart reports if the column is one of the primary key columns of the table.
+func isPrimaryKeyPart(table *schema.Table, column *schema.Column) bool {
+	if table.PrimaryKey == nil {
+		return false
+	}
+	for _, p := range table.PrimaryKey.Parts {
+		if p.C != nil && p.C.Name == column.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// isWeakEntity reports if the table is a weak entity, i.e. a table that is not a join table, and whose composite
+// primary key includes a foreign key column (e.g. an "order_items" table keyed by "order_id" and "line").
+func isWeakEntity(table *schema.Table) bool {
+	pk := table.PrimaryKey
+	if pk == nil || len(pk.Parts) < 2 || isJoinTable(table) {
+		return false
+	}
+	var fk bool
+	for _, p := range pk.Parts {
+		if p.C == nil {
+			return false
+		}
+		fk = fk || isForeignKey(table, p.C)
+	}
+	return fk
+}
+
+// Note: at this moment ent doesn't support fields on m2m relations.
+func isJoinTable(table *schema.Table) bool {
+	if table.PrimaryKey == nil || len(table.PrimaryKey.Parts) != 2 || len(table.ForeignKeys) != 2 {
+		return false
+	}
+	// Make sure that the foreign key columns exactly match primary key column.
+	for _, fk := range table.ForeignKeys {
+		if len(fk.Columns) != 1 {
+			return false
+		}
+		if fk.Columns[0] != table.PrimaryKey.Parts[0].C && fk.Columns[0] != table.PrimaryKey.Parts[1].C {
+			return false
+		}
+	}
+	return true
+}
+
+// isForeignKey reports if the column is a foreign key column of the table.
+func isForeignKey(table *schema.Table, column *schema.Column) bool {
+	for _, fk := range table.ForeignKeys {
+		for _, c := range fk.Columns {
+			if c.Name == column.Name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// reservedNames holds the (lowercased) field names that conflict with the fields and methods of the entities
+// generated by ent, e.g. the "Edges" field and the "Update" method. Non-key columns named "id" conflict with the
+// "id" field of the primary key.
+var reservedNames = map[string]bool{
+	"id":     true,
+	"edges":  true,
+	"update": true,
+	"unwrap": true,
+	"string": true,
+}
+
+// fieldName returns the snake-cased field name of a column, e.g. "user_name" for "userName", and "http_code"
+// for "HTTPCode".
+func fieldName(column string) string {
+	var (
+		b     strings.Builder
+		runes = []rune(column)
+	)
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			next := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || unicode.IsUpper(prev) && next {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// hasColumn reports if the table has a column with the given name.
+func hasColumn(table *schema.Table, name string) bool {
+	_, ok := table.Column(name)
+	return ok
+}
+
+// renamedField returns the name of a field that is renamed for conflicting with a reserved name or a Go keyword,
+// e.g. "id_field" for an "id" column. The "_field" suffix is repeated if the table has a column named as the field.
+func renamedField(table *schema.Table, name string) string {
+	name += "_field"
+	for {
+		if _, ok := table.Column(name); !ok {
+			return name
+		}
+		name += "_field"
+	}
+}
+
+// renamedTable is a table attribute holding the schema and the name of a table that is renamed for having the
+// same name as a table of another imported schema.
+type renamedTable struct {
+	schema.Attr
+	Schema string
+	Name   string
+}
+
+// sqlTableName returns the name of the table in the database.
+func sqlTableName(table *schema.Table) string {
+	for _, a := range table.Attrs {
+		if r, ok := a.(*renamedTable); ok {
+			return r.Name
+		}
+	}
+	return table.Name
+}
+
+// typeName returns the Go type name of the given table. Characters that are not valid in Go identifiers
+// (e.g. spaces, hyphens or dots) are used as word separators, and names starting with a digit are prefixed.
+func typeName(tableName string) string {
+	name := inflect.Camelize(inflect.Singularize(strings.Map(func(r rune) rune {
+		if r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return ' '
+	}, tableName)))
+	if r, _ := utf8.DecodeRuneInString(name); unicode.IsDigit(r) {
+		name = "T" + name
+	}
+	return name
+}
+
+func tableName(typeName string) string {
+	return inflect.Underscore(inflect.Pluralize(typeName))
+}
+
+// Plural implements the Inflector interface.
+func (defaultInflector) Plural(typeName string) string {
+	return tableName(typeName)
+}
+
+// Singular implements the Inflector interface.
+func (defaultInflector) Singular(edgeName string) string {
+	return inflect.Singularize(edgeName)
+}
+
+// inflector returns the inflector of the edge names.
+func (i *ImportOptions) inflector() Inflector {
+	if i.edgeInflector != nil {
+		return i.edgeInflector
+	}
+	return defaultInflector{}
+}
+
+// resolvePrimaryKey returns the primary key as an ent field for a given table.
+func resolvePrimaryKey(i *ImportOptions, columnField fieldFunc, table *schema.Table) (f ent.Field, err error) {
+	// A primary key without parts may be returned by the inspection, and is handled as a missing one.
+	if table.PrimaryKey == nil || len(table.PrimaryKey.Parts) == 0 {
+		return nil, fmt.Errorf("entimport: missing primary key (table: %v)", table.Name)
+	}
+	if len(table.PrimaryKey.Parts) != 1 {
+		return nil, fmt.Errorf("entimport: invalid primary key, single part key must be present (table: %v, got: %v parts)", table.Name, len(table.PrimaryKey.Parts))
+	}
+	if table.PrimaryKey.Parts[0].C == nil {
+		return nil, fmt.Errorf("entimport: invalid primary key, key part must be a column (table: %v)", table.Name)
+	}
+	c := table.PrimaryKey.Parts[0].C
+	if f, err = columnField(c); err != nil {
+		return nil, err
+	}
+	f = decimalKey(i, c, f)
+	if d := f.Descriptor(); d.Name != "id" {
+		d.StorageKey = d.Name
+		d.Name = "id"
+	}
+	return f, nil
+}
+
+// decimalKey converts the field of a decimal key column (or a column referencing one) to a string field. Decimal
+// keys are imported as string ids, as float ids lose precision (and ent does not accept them as ids), keeping
+// their type in the database.
+func decimalKey(i *ImportOptions, c *schema.Column, f ent.Field) ent.Field {
+	d, ok := c.Type.Type.(*schema.DecimalType)
+	if !ok {
+		return f
+	}
+	desc := f.Descriptor()
+	s := field.String(desc.Name).
+		SchemaType(map[string]string{
+			i.driver.Dialect: decimalType(d), // Override the dialect.
+		})
+	s.Descriptor().Optional, s.Descriptor().Comment = desc.Optional, desc.Comment
+	return s
+}
+
+// decimalField returns the field of a decimal column, keeping its precision and scale in the database. Decimals
+// without fractional digits (a scale of 0) that fit in 64 bits are mapped to integer fields, and other decimals are
+// mapped to float fields.
+func decimalField(dlct string, typ *schema.DecimalType, name string) ent.Field {
+	var f ent.Field
+	switch {
+	case typ.Precision == 0 || typ.Scale > 0 || typ.Precision > 18:
+		f = field.Float(name)
+	case typ.Precision <= 9 && typ.Unsigned:
+		f = field.Uint32(name)
+	case typ.Precision <= 9:
+		f = field.Int32(name)
+	case typ.Unsigned:
+		f = field.Uint64(name)
+	default:
+		f = field.Int(name)
+	}
+	f.Descriptor().SchemaType = map[string]string{
+		dlct: decimalType(typ), // Override the dialect.
+	}
+	return f
+}
+
+// decimalType returns the database type of a decimal column, e.g. "decimal(20,0) unsigned".
+func decimalType(d *schema.DecimalType) string {
+	t := d.T
+	if d.Precision > 0 {
+		t = fmt.Sprintf("%s(%d,%d)", t, d.Precision, d.Scale)
+	}
+	if d.Unsigned {
+		t += " unsigned"
+	}
+	return t
+}
+
+// upsertNode handles the creation of a node from a given table.
+func upsertNode(i *ImportOptions, field fieldFunc, table *schema.Table) (*schemast.UpsertSchema, error) {
+	if err := i.checkExcludedColumns(table); err != nil {
+		return nil, err
+	}
+	upsert := &schemast.UpsertSchema{
+		Name: i.typeNamePrefix + typeName(table.Name),
+	}
+	if name := sqlTableName(table); !i.noAnnotations && tableName(upsert.Name) != name {
+		upsert.Annotations = []entschema.Annotation{
+			entsql.Annotation{Table: name},
+		}
+	}
+	if !i.noAnnotations && i.tableAnnots != nil {
+		upsert.Annotations = append(upsert.Annotations, i.tableAnnots(table.Name)...)
+	}
+	fields := make(map[string]ent.Field, len(upsert.Fields))
+	for _, f := range upsert.Fields {
+		fields[f.Descriptor().StorageKey] = f
+	}
+	// The columns of composite primary keys of weak entities are imported as fields (and edges) with a
+	// unique index, as ent does not support composite ids.
+	weak := isWeakEntity(table)
+	var (
+		pk  ent.Field
+		err error
+	)
+	if weak {
+		upsert.Annotations = append(upsert.Annotations, &commentAnnotation{
+			Method: "Fields",
+			Text: fmt.Sprintf("entimport: the composite primary key (%s) of table %q is imported as a unique index, as ent does not support composite ids; ent generates an \"id\" field for the schema",
+				columnNames(primaryKeyColumns(table)), table.Name),
+		})
+	} else {
+		if pk, err = resolvePrimaryKey(i, field, table); err != nil {
+			return nil, err
+		}
+		pk.Descriptor().Immutable = i.immutableCols[table.PrimaryKey.Parts[0].C.Name]
+		i.sourceComment(table, table.PrimaryKey.Parts[0].C, pk)
+		if _, ok := fields[pk.Descriptor().StorageKey]; !ok {
+			fields[pk.Descriptor().StorageKey] = pk
+			upsert.Fields = append(upsert.Fields, pk)
+		}
+		if i.noPKStorageKey {
+			pk.Descriptor().StorageKey = ""
+		}
+	}
+	for _, column := range table.Columns {
+		if !weak && isPrimaryKey(table, column) {
+			continue
+		}
+		if !i.includeColumn(table.Name, column.Name) {
+			continue
+		}
+		fld, err := field(column)
+		if err != nil {
+			return nil, err
+		}
+		if isForeignKey(table, column) {
+			fld = decimalKey(i, column, fld)
+		}
+		i.sourceComment(table, column, fld)
+		i.timeDefaults(column, fld)
+		i.matchValidators(table, column, fld)
+		fld = i.enumColumn(table.Name, column, fld)
+		i.enumGoType(table.Name, fld)
+		i.bytesGoType(table.Name, fld)
+		// Edge fields cannot be immutable in ent, and foreign key columns are skipped.
+		if i.immutableCols[column.Name] && !isForeignKey(table, column) {
+			fld.Descriptor().Immutable = true
+		}
+		// Columns that are not named by ent's convention (e.g. "userName") are imported as snake-cased
+		// fields, and keep their names as storage keys.
+		if d := fld.Descriptor(); d.StorageKey == "" && d.Name == column.Name {
+			if name := fieldName(column.Name); name != d.Name && !hasColumn(table, name) {
+				d.StorageKey = column.Name
+				d.Name = name
+			}
+		}
+		if d := fld.Descriptor(); i.safeIdentifiers && token.Lookup(d.Name).IsKeyword() {
+			d.StorageKey = d.Name
+			d.Name = renamedField(table, d.Name)
+		}
+		// Fields that conflict with the generated entity (e.g. an "edges" column, or an "id" column of a table
+		// whose primary key is named differently) are renamed regardless of WithSafeIdentifiers, as they fail
+		// the code generation.
+		if d := fld.Descriptor(); reservedNames[strings.ToLower(d.Name)] {
+			d.StorageKey = column.Name
+			d.Name = renamedField(table, d.Name)
+		}
+		if name, ok := i.fieldNames[table.Name+"."+column.Name]; ok {
+			d := fld.Descriptor()
+			d.StorageKey = column.Name
+			d.Name = name
+		}
+		if _, ok := fields[column.Name]; !ok {
+			fields[column.Name] = fld
+			upsert.Fields = append(upsert.Fields, fld)
+		}
+	}
+	// Unique indexes on nullable columns are kept on their (optional) fields, as multiple NULL values
+	// do not violate a unique index. Indexes on expressions are skipped, as they have no column.
+	for _, index := range table.Indexes {
+		if index.Unique && len(index.Parts) == 1 && index.Parts[0].C != nil {
+			if fld, ok := fields[index.Parts[0].C.Name]; ok {
+				fld.Descriptor().Unique = true
+			}
+		}
+	}
+	upsert.Indexes = compositeIndexes(table, fields)
+	if weak {
+		names := make([]string, 0, len(table.PrimaryKey.Parts))
+		for _, c := range primaryKeyColumns(table) {
+			if fld, ok := fields[c.Name]; ok {
+				names = append(names, fld.Descriptor().Name)
+			}
+		}
+		// Columns that were not included by WithIncludedColumns leave the key without an index.
+		if len(names) == len(table.PrimaryKey.Parts) {
+			upsert.Indexes = append([]ent.Index{index.Fields(names...).Unique()}, upsert.Indexes...)
+		}
+	}
+	if i.polymorphic {
+		polymorphicHints(upsert, table, fields)
+	}
+	for _, fk := range table.ForeignKeys {
+		for _, column := range fk.Columns {
+			if !i.includeColumn(table.Name, column.Name) {
+				continue
+			}
+			// FK / Reference column
+			fld, ok := fields[column.Name]
+			if !ok {
+				return nil, fmt.Errorf("foreign key for column: %q doesn't exist in referenced table", column.Name)
+			}
+			fld.Descriptor().Optional = true
+		}
+	}
+	if i.fieldGrouping {
+		groupFields(upsert, table)
+	}
+	if i.idPosition == Last && pk != nil {
+		fields := make([]ent.Field, 0, len(upsert.Fields))
+		for _, f := range upsert.Fields {
+			if f != pk {
+				fields = append(fields, f)
+			}
+		}
+		upsert.Fields = append(fields, pk)
+	}
+	return upsert, err
+}
+
+// polymorphicHints adds a comment to the edges of the node for each polymorphic association of the table, i.e.
+// a pair of imported <name>_type and <name>_id columns, where the id column is not a foreign key.
+func polymorphicHints(upsert *schemast.UpsertSchema, table *schema.Table, fields map[string]ent.Field) {
+	for _, column := range table.Columns {
+		name := strings.TrimSuffix(column.Name, "_type")
+		if name == "" || name == column.Name {
+			continue
+		}
+		id, ok := table.Column(name + "_id")
+		if !ok || isForeignKey(table, id) {
+			continue
+		}
+		if _, ok := fields[column.Name]; !ok {
+			continue
+		}
+		if _, ok := fields[id.Name]; !ok {
+			continue
+		}
+		upsert.Annotations = append(upsert.Annotations, &commentAnnotation{
+			Method: "Edges",
+			Text: fmt.Sprintf("entimport: columns %s and %s form a polymorphic association %q, which is not imported as an edge, as ent does not support edges to multiple types",
+				column.Name, id.Name, name),
+		})
+	}
+}
+
+// compositeIndexes returns the indexes of the table that span multiple columns. The primary key, indexes on
+// expressions and indexes on columns that were not imported are skipped, and single-column unique indexes are
+// represented by the Unique option of their fields.
+func compositeIndexes(table *schema.Table, fields map[string]ent.Field) []ent.Index {
+	var indexes []ent.Index
+	for _, idx := range table.Indexes {
+		if len(idx.Parts) < 2 || isPrimaryKeyIndex(table, idx) {
+			continue
+		}
+		names := make([]string, 0, len(idx.Parts))
+		for _, p := range idx.Parts {
+			if p.C == nil {
+				break
+			}
+			fld, ok := fields[p.C.Name]
+			if !ok {
+				break
+			}
+			names = append(names, fld.Descriptor().Name)
+		}
+		if len(names) != len(idx.Parts) {
+			continue
+		}
+		i := index.Fields(names...)
+		if idx.Unique {
+			i = i.Unique()
+		}
+		indexes = append(indexes, i)
+	}
+	return indexes
+}
+
+// primaryKeyColumns returns the columns of the primary key of the table.
+func primaryKeyColumns(table *schema.Table) []*schema.Column {
+	columns := make([]*schema.Column, 0, len(table.PrimaryKey.Parts))
+	for _, p := range table.PrimaryKey.Parts {
+		columns = append(columns, p.C)
+	}
+	return columns
+}
+
+// isPrimaryKeyIndex reports if the index is the primary key of the table.
+func isPrimaryKeyIndex(table *schema.Table, idx *schema.Index) bool {
+	pk := table.PrimaryKey
+	if pk == nil || len(pk.Parts) != len(idx.Parts) {
+		return false
+	}
+	if idx == pk {
+		return true
+	}
+	for i, p := range idx.Parts {
+		if p.C == nil || pk.Parts[i].C == nil || p.C.Name != pk.Parts[i].C.Name {
+			return false
+		}
+	}
+	return true
+}
+
+// groupFields orders the fields of the node by groups: id, scalar fields and foreign key fields.
+func groupFields(upsert *schemast.UpsertSchema, table *schema.Table) {
+	fks := make(map[string]bool)
+	for _, fk := range table.ForeignKeys {
+		for _, c := range fk.Columns {
+			fks[c.Name] = true
+		}
+	}
+	group := func(f ent.Field) int {
+		switch d := f.Descriptor(); {
+		case d.Name == "id":
+			return 0
+		case fks[d.StorageKey] || d.StorageKey == "" && fks[d.Name]:
+			return 2
+		default:
+			return 1
+		}
+	}
+	sort.SliceStable(upsert.Fields, func(i, j int) bool {
+		return group(upsert.Fields[i]) < group(upsert.Fields[j])
+	})
+}
+
+// applyColumnAttributes adds column attributes to a given ent field.
+func applyColumnAttributes(f ent.Field, col *schema.Column) {
+	desc := f.Descriptor()
+	// Optional follows only the nullability of the column. Columns with a default value (e.g. NOT NULL
+	// DEFAULT CURRENT_TIMESTAMP) are not optional, as ent requires their values on creation.
+	desc.Optional = col.Type.Null
+	for _, attr := range col.Attrs {
+		if a, ok := attr.(*schema.Comment); ok {
+			desc.Comment = a.Text
+		}
+	}
+	// The defaults of enum and bytes columns are handled by enumDefault and bytesDefault.
+	if t := desc.Info.Type; t != field.TypeEnum && t != field.TypeBytes {
+		funcDefault(f, col)
+	}
+}
+
+// defaultFuncs maps the functions that are used as column defaults to their ent equivalents. The
+// returned annotation is nil in case the function has no equivalent for the type of the field.
+var defaultFuncs = map[string]func(desc *field.Descriptor, expr string) entschema.Annotation{
+	"now":               timeNow,
+	"current_timestamp": timeNow,
+	"localtimestamp":    timeNow,
+	"gen_random_uuid":   uuidNew,
+	"uuid_generate_v4":  uuidNew,
+	"uuid":              uuidNew,
+	"nextval":           dbGenerated,
+}
+
+// timeNow sets time.Now as the default value of time fields.
+func timeNow(desc *field.Descriptor, _ string) entschema.Annotation {
+	if desc.Info.Type != field.TypeTime {
+		return nil
+	}
+	return &callAnnotation{Method: "Default", Args: []string{"time.Now"}, Imports: []string{"time"}}
+}
+
+// sourceComment sets the comment of the field from the comment source (see WithCommentSource), in case its
+// column has no comment in the database.
+func (i *ImportOptions) sourceComment(table *schema.Table, column *schema.Column, f ent.Field) {
+	if desc := f.Descriptor(); desc.Comment == "" {
+		desc.Comment = i.comments[table.Name+"."+column.Name]
+	}
+}
+
+// reCheckMatch matches CHECK constraints of regular expressions on a single column, as returned by Postgres,
+// e.g. ((code)::text ~ '^[A-Z]{3}$'::text).
+var reCheckMatch = regexp.MustCompile(`^\(*"?(\w+)"?\)?(?:::[\w ]+)?\s+(~\*?)\s+'((?:[^']|'')*)'(?:::[\w ]+)?\)*$`)
+
+// matchValidators adds a Match validator to string fields for the regular expression CHECK constraints of their
+// columns (see WithRegexValidators).
+func (i *ImportOptions) matchValidators(table *schema.Table, column *schema.Column, f ent.Field) {
+	desc := f.Descriptor()
+	if !i.regexValidators || desc.Info.Type != field.TypeString {
+		return
+	}
+	for _, attr := range table.Attrs {
+		check, ok := attr.(*schema.Check)
+		if !ok {
+			continue
+		}
+		m := reCheckMatch.FindStringSubmatch(strings.TrimSpace(check.Expr))
+		if m == nil || m[1] != column.Name {
+			continue
+		}
+		expr := strings.ReplaceAll(m[3], "''", "'")
+		if m[2] == "~*" {
+			expr = "(?i)" + expr
+		}
+		if _, err := regexp.Compile(expr); err != nil {
+			continue
+		}
+		desc.Annotations = append(desc.Annotations, &callAnnotation{
+			Method:  "Match",
+			Args:    []string{fmt.Sprintf("regexp.MustCompile(%q)", expr)},
+			Imports: []string{"regexp"},
+		})
+	}
+}
+
+// timeDefaults sets time.Now as the default value of time fields of audit columns (see WithTimeDefaults), in case
+// their columns have no default function. Fields of updated columns are also set to time.Now on update.
+func (i *ImportOptions) timeDefaults(column *schema.Column, f ent.Field) {
+	desc := f.Descriptor()
+	if desc.Info.Type != field.TypeTime {
+		return
+	}
+	created, updated := matchColumn(i.createdColumns, column.Name), matchColumn(i.updatedColumns, column.Name)
+	if !created && !updated {
+		return
+	}
+	hasDefault := false
+	for _, a := range desc.Annotations {
+		if a, ok := a.(*callAnnotation); ok && a.Method == "Default" {
+			hasDefault = true
+		}
+	}
+	if !hasDefault {
+		desc.Annotations = append(desc.Annotations, timeNow(desc, ""))
+	}
+	if updated {
+		desc.Annotations = append(desc.Annotations, &callAnnotation{Method: "UpdateDefault", Args: []string{"time.Now"}, Imports: []string{"time"}})
+	}
+}
+
+// matchColumn reports if the column name matches one of the given patterns.
+func matchColumn(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// uuidNew sets uuid.New as the default value of UUID fields, and uuid.NewString of string fields.
+func uuidNew(desc *field.Descriptor, _ string) entschema.Annotation {
+	switch desc.Info.Type {
+	case field.TypeUUID:
+		return &callAnnotation{Method: "Default", Args: []string{"uuid.New"}, Imports: []string{"github.com/google/uuid"}}
+	case field.TypeString:
+		return &callAnnotation{Method: "DefaultFunc", Args: []string{"uuid.NewString"}, Imports: []string{"github.com/google/uuid"}}
+	}
+	return nil
+}
+
+// dbGenerated notes that the values of the field are generated by the database (e.g. by a sequence), as ent
+// has no equivalent default.
+func dbGenerated(desc *field.Descriptor, expr string) entschema.Annotation {
+	return &commentAnnotation{
+		Text: fmt.Sprintf("entimport: values of field %q are generated by the database default %s", desc.Name, expr),
+	}
+}
+
+// funcDefault sets the ent default of a field whose column default is a function of the database, using
+// the defaultFuncs registry. Unknown functions are added as a comment.
+func funcDefault(f ent.Field, col *schema.Column) {
+	expr, name, ok := defaultFuncName(col)
+	if !ok {
+		return
+	}
+	desc := f.Descriptor()
+	var an entschema.Annotation
+	if fn, ok := defaultFuncs[name]; ok {
+		an = fn(desc, expr)
+	}
+	if an == nil {
+		an = &commentAnnotation{
+			Text: fmt.Sprintf("entimport: default value %s of column %q is not supported", expr, col.Name),
+		}
+	}
+	desc.Annotations = append(desc.Annotations, an)
+}
+
+// boolDefault sets the default value of a boolean column on its field. Postgres accepts several forms of boolean
+// literals (e.g. true, 't', 'yes', 'on' and '1'), which may also be casted, e.g. 'f'::boolean, and MySQL stores
+// the defaults of tinyint(1) columns as numbers (e.g. 1). Other expressions are added as a comment, and function
+// calls are left to funcDefault.
+func boolDefault(f ent.Field, column *schema.Column) {
+	x := columnDefault(column)
+	if _, _, call := defaultFuncName(column); x == "" || call {
+		return
+	}
+	v := strings.TrimSpace(x)
+	if idx := strings.LastIndex(v, "::"); idx != -1 {
+		v = v[:idx]
+	}
+	if u, ok := unquote(v); ok && v[0] == '\'' {
+		v = strings.TrimSpace(u)
+	}
+	desc := f.Descriptor()
+	switch strings.ToLower(v) {
+	case "true", "t", "yes", "y", "on", "1":
+		desc.Default = true
+	case "false", "f", "no", "n", "off", "0":
+		desc.Default = false
+	case "null":
+	default:
+		desc.Annotations = append(desc.Annotations, &commentAnnotation{
+			Text: fmt.Sprintf("entimport: default value %s of column %q is not supported", x, column.Name),
+		})
+	}
+}
+
+// defaultFuncName returns the default expression of the column and the lowercased name of its function,
+// and reports if the default is a function call (e.g. "now()") or a function keyword (e.g. "CURRENT_TIMESTAMP").
+func defaultFuncName(col *schema.Column) (expr, name string, ok bool) {
+	switch x := col.Default.(type) {
+	case *schema.RawExpr:
+		expr = x.X
+	case *schema.Literal:
+		expr = x.V
+	default:
+		return "", "", false
+	}
+	x := strings.TrimSpace(expr)
+	// MySQL expression defaults are wrapped with parentheses, e.g. "(uuid())".
+	if strings.HasPrefix(x, "(") && strings.HasSuffix(x, ")") {
+		x = x[1 : len(x)-1]
+	}
+	// Postgres defaults may be casted to the column type, e.g. "now()::timestamp".
+	if idx := strings.LastIndex(x, "::"); idx != -1 && idx > strings.LastIndex(x, ")") {
+		x = x[:idx]
+	}
+	name, args, call := strings.Cut(x, "(")
+	if name == "" || (call && !strings.HasSuffix(args, ")")) {
+		return "", "", false
+	}
+	for i, r := range name {
+		if r != '_' && r != '.' && !unicode.IsLetter(r) && (i == 0 || !unicode.IsDigit(r)) {
+			return "", "", false
+		}
+	}
+	name = strings.ToLower(name)
+	switch name {
+	case "true", "false", "null":
+		return "", "", false
+	}
+	// Keywords that are not called with parentheses are known functions only.
+	if _, known := defaultFuncs[name]; !call && !known {
+		return "", "", false
+	}
+	return expr, name, true
+}
+
+// enumField returns an enum field with the given values. Values with spaces (e.g. " in progress ") are
+// given names (e.g. "InProgress"), as they cannot be used as Go identifiers in the generated code.
+func enumField(name string, values []string) ent.Field {
+	var (
+		pairs []string
+		named bool
+		taken = make(map[string]bool, len(values))
+	)
+	for _, v := range values {
+		taken[strings.ToLower(v)] = true
+	}
+	for _, v := range values {
+		n := v
+		if strings.ContainsAny(v, " \t") {
+			if n = inflect.Camelize(v); n == "" {
+				n = "Blank"
+			}
+			for i, base := 2, n; taken[strings.ToLower(n)]; i++ {
+				n = fmt.Sprintf("%s%d", base, i)
+			}
+			taken[strings.ToLower(n)] = true
+			named = true
+		}
+		pairs = append(pairs, n, v)
+	}
+	if !named {
+		return field.Enum(name).Values(values...)
+	}
+	return field.Enum(name).NamedValues(pairs...)
+}
+
+// enumDefault sets the default value of an enum field, in case it is one of the enum values.
+// Otherwise, the default value is added as a comment.
+func enumDefault(f ent.Field, column *schema.Column, values []string) {
+	var x string
+	switch d := column.Default.(type) {
+	case *schema.Literal:
+		x = d.V
+	case *schema.RawExpr:
+		x = d.X
+	default:
+		return
+	}
+	// Postgres defaults are casted to the enum type, e.g. 'a'::status.
+	if idx := strings.LastIndex(x, "::"); idx != -1 {
+		x = x[:idx]
+	}
+	desc := f.Descriptor()
+	if v, ok := unquote(x); ok {
+		for _, e := range values {
+			if e == v {
+				desc.Default = v
+				return
+			}
+		}
+	}
+	desc.Annotations = append(desc.Annotations, &commentAnnotation{
+		Text: fmt.Sprintf("entimport: default value %s of column %q is not one of its enum values", x, column.Name),
+	})
+}
+
+// unquote returns the value of a string literal quoted with single or double quotes, and reports if it was quoted.
+func unquote(s string) (string, bool) {
+	if len(s) < 2 || s[0] != s[len(s)-1] {
+		return s, false
+	}
+	switch s[0] {
+	case '"':
+		v, err := strconv.Unquote(s)
+		return v, err == nil
+	case '\'':
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'"), true
+	}
+	return s, false
+}
+
+// schemaMutations is in charge of creating all the schema mutations needed for an ent schema.
+func schemaMutations(i *ImportOptions, field fieldFunc, tables []*schema.Table) ([]schemast.Mutator, error) {
+	if len(tables) == 0 {
+		return nil, ErrNoTables
+	}
+	if i.maxTables > 0 && len(tables) > i.maxTables {
+		return nil, fmt.Errorf("entimport: found %d tables to import, exceeding the limit of %d tables; narrow the selection of tables to import", len(tables), i.maxTables)
+	}
+	if len(i.inferPatterns) > 0 {
+		var err error
+		if tables, err = inferForeignKeys(tables, i.inferPatterns); err != nil {
+			return nil, err
+		}
+	}
+	var errs []error
+	mutations := make(map[string]schemast.Mutator)
+	joinTables := make(map[string]*schema.Table)
+	for _, table := range tables {
+		if isJoinTable(table) {
+			joinTables[table.Name] = table
+			continue
+		}
+		node, err := upsertNode(i, field, table)
+		if err != nil {
+			err = fmt.Errorf("entimport: issue with table %v: %w", table.Name, err)
+			if !i.collectErrors {
+				return nil, err
+			}
+			errs = append(errs, err)
+			continue
+		}
+		mutations[table.Name] = node
+	}
+	if i.validateFKs {
+		if err := validateForeignKeys(i, mutations, tables); err != nil {
+			if !i.collectErrors {
+				return nil, err
+			}
+			errs = append(errs, err)
+		}
+	}
+	for _, table := range tables {
+		if t, ok := joinTables[table.Name]; ok {
+			err := upsertManyToMany(i, mutations, t)
+			if err != nil {
+				if !i.collectErrors {
+					return nil, err
+				}
+				errs = append(errs, err)
+			}
+			continue
+		}
+		upsertOneToX(i, mutations, table)
+	}
+	inheritMixins(mutations, tables)
+	if i.autoTimeMixin {
+		timeMixin(mutations, tables)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	ml := make([]schemast.Mutator, 0, len(mutations))
+	for _, mutator := range mutations {
+		ml = append(ml, mutator)
+	}
+	return ml, nil
+}
+
+// inheritMixins moves the fields of the columns that tables inherit from their parent tables (see Inherits) to
+// mixins generated from the parents, which are shared by the inheriting types. Primary keys are not moved, and
+// the mixins are collected before changing any type, as parent tables may inherit from other tables.
+func inheritMixins(mutations map[string]schemast.Mutator, tables []*schema.Table) {
+	byName := make(map[string]*schema.Table, len(tables))
+	for _, t := range tables {
+		byName[t.Name] = t
+	}
+	type inherited struct {
+		child   *schemast.UpsertSchema
+		mixin   *mixinAnnotation
+		columns []string
+	}
+	var list []inherited
+	for _, t := range tables {
+		child, ok := mutations[t.Name].(*schemast.UpsertSchema)
+		if !ok {
+			continue
+		}
+		for _, a := range t.Attrs {
+			inh, ok := a.(*Inherits)
+			if !ok {
+				continue
+			}
+			parentTable, ok := byName[inh.Parent]
+			if !ok {
+				continue
+			}
+			parent, ok := mutations[parentTable.Name].(*schemast.UpsertSchema)
+			if !ok {
+				continue
+			}
+			in := inherited{child: child, mixin: &mixinAnnotation{Type: parent.Name + "Mixin"}}
+			for _, c := range parentTable.Columns {
+				if isPrimaryKey(parentTable, c) || isPrimaryKey(t, c) {
+					continue
+				}
+				f, ok := lookupField(parent, c.Name)
+				if !ok {
+					continue
+				}
+				// The mixin holds copies of the fields, as the annotations of the generated fields are
+				// modified when the schema is written.
+				desc := *f.Descriptor()
+				desc.Annotations = append([]entschema.Annotation(nil), desc.Annotations...)
+				in.mixin.Fields = append(in.mixin.Fields, descField{desc: &desc})
+				in.columns = append(in.columns, c.Name)
+			}
+			if len(in.columns) > 0 {
+				list = append(list, in)
+			}
+		}
+	}
+	for _, in := range list {
+		for _, c := range in.columns {
+			removeField(in.child, c)
+		}
+		in.child.Annotations = append(in.child.Annotations, in.mixin)
+	}
+}
+
+// timeMixinColumns are the columns that are extracted into the TimeMixin (see WithAutoTimeMixin).
+var timeMixinColumns = []string{"created_at", "updated_at"}
+
+// timeMixin moves the created_at and updated_at time fields of the types into a shared TimeMixin, in case they are
+// declared identically by at least two types. Types that declare them differently (e.g. as optional fields) keep them.
+func timeMixin(mutations map[string]schemast.Mutator, tables []*schema.Table) {
+	const name = "TimeMixin"
+	var (
+		mixin *mixinAnnotation
+		nodes []*schemast.UpsertSchema
+	)
+	for _, t := range tables {
+		node, ok := mutations[t.Name].(*schemast.UpsertSchema)
+		if !ok {
+			continue
+		}
+		// The mixin type name is taken by an imported table (e.g. "time_mixins").
+		if node.Name == name {
+			return
+		}
+		var fields []ent.Field
+		for _, c := range timeMixinColumns {
+			f, ok := lookupField(node, c)
+			if !ok || f.Descriptor().Name == "id" || f.Descriptor().Info.Type != field.TypeTime {
+				break
+			}
+			fields = append(fields, f)
+		}
+		if len(fields) != len(timeMixinColumns) {
+			continue
+		}
+		if mixin == nil {
+			mixin = &mixinAnnotation{Type: name}
+			for _, f := range fields {
+				desc := *f.Descriptor()
+				desc.Annotations = append([]entschema.Annotation(nil), desc.Annotations...)
+				mixin.Fields = append(mixin.Fields, descField{desc: &desc})
+			}
+		}
+		same := true
+		for j, f := range fields {
+			same = same && reflect.DeepEqual(f.Descriptor(), mixin.Fields[j].Descriptor())
+		}
+		if same {
+			nodes = append(nodes, node)
+		}
+	}
+	if len(nodes) < 2 {
+		return
+	}
+	for _, node := range nodes {
+		for _, c := range timeMixinColumns {
+			removeField(node, c)
+		}
+		node.Annotations = append(node.Annotations, mixin)
+	}
+}
+
+// descField is a field with the given descriptor.
+type descField struct {
+	desc *field.Descriptor
+}
+
+// Descriptor implements the ent.Field interface.
+func (f descField) Descriptor() *field.Descriptor { return f.desc }
+
+// removeField removes the field of the given node by its name, or by the name of the column it is stored in.
+func removeField(node *schemast.UpsertSchema, name string) {
+	fields := node.Fields[:0]
+	for _, f := range node.Fields {
+		if d := f.Descriptor(); d.StorageKey != name && (d.StorageKey != "" || d.Name != name) {
+			fields = append(fields, f)
+		}
+	}
+	node.Fields = fields
+}
+
+// inferForeignKeys returns the tables with foreign keys inferred from the names of their columns, for databases
+// without foreign key constraints. A pattern is a column name with a "{table}" placeholder (e.g. "{table}_id"),
+// that matches columns referencing the primary key of the table with the given (singular or plural) name.
+func inferForeignKeys(tables []*schema.Table, patterns []string) ([]*schema.Table, error) {
+	type pattern struct{ prefix, suffix string }
+	ps := make([]pattern, 0, len(patterns))
+	for _, p := range patterns {
+		if strings.Count(p, "{table}") != 1 {
+			return nil, fmt.Errorf("entimport: invalid edge inference pattern %q, expect one {table} placeholder", p)
+		}
+		prefix, suffix, _ := strings.Cut(p, "{table}")
+		ps = append(ps, pattern{prefix: prefix, suffix: suffix})
+	}
+	refs := make(map[string]*schema.Table, len(tables))
+	for _, t := range tables {
+		if t.PrimaryKey != nil && len(t.PrimaryKey.Parts) == 1 && t.PrimaryKey.Parts[0].C != nil {
+			refs[t.Name] = t
+		}
+	}
+	inferred := make([]*schema.Table, len(tables))
+	for idx, t := range tables {
+		inferred[idx] = t
+		fks := make(map[string]bool)
+		for _, fk := range t.ForeignKeys {
+			for _, c := range fk.Columns {
+				fks[c.Name] = true
+			}
+		}
+		for _, c := range t.Columns {
+			if fks[c.Name] {
+				continue
+			}
+			for _, p := range ps {
+				name := strings.TrimSuffix(strings.TrimPrefix(c.Name, p.prefix), p.suffix)
+				if name == "" || len(name)+len(p.prefix)+len(p.suffix) != len(c.Name) {
+					continue
+				}
+				ref, ok := refs[inflect.Pluralize(name)]
+				if !ok {
+					ref, ok = refs[name]
+				}
+				if !ok || ref == t {
+					continue
+				}
+				// The table is copied, as the inspected table is not changed.
+				if inferred[idx] == t {
+					copied := *t
+					copied.ForeignKeys = append([]*schema.ForeignKey(nil), t.ForeignKeys...)
+					inferred[idx] = &copied
+				}
+				inferred[idx].ForeignKeys = append(inferred[idx].ForeignKeys, &schema.ForeignKey{
+					Table:      inferred[idx],
+					Columns:    []*schema.Column{c},
+					RefTable:   ref,
+					RefColumns: []*schema.Column{ref.PrimaryKey.Parts[0].C},
+				})
+				break
+			}
+		}
+	}
+	return inferred, nil
+}
+
+// O2O Two Types - Child Table has a unique reference (FK) to Parent table
 // O2O Same Type - Child Table has a unique reference (FK) to Parent table (itself)
 // O2M (The "Many" side, keeps a reference to the "One" side).
 // O2M Two Types - Parent has a non-unique reference to Child, and Child has a unique back-reference to Parent
 // O2M Same Type - Parent has a non-unique reference to Child, and Child doesn't have a back-reference to Parent.
-func upsertOneToX(mutations map[string]schemast.Mutator, table *schema.Table) {
+func upsertOneToX(i *ImportOptions, mutations map[string]schemast.Mutator, table *schema.Table) {
 	if table.ForeignKeys == nil {
 		return
 	}
 	idxs := make(map[string]*schema.Index)
 	for _, idx := range table.Indexes {
-		if len(idx.Parts) != 1 {
+		if len(idx.Parts) != 1 || idx.Parts[0].C == nil {
 			continue
 		}
 		idxs[idx.Parts[0].C.Name] = idx
 	}
 	for _, fk := range table.ForeignKeys {
 		if len(fk.Columns) != 1 {
+			// Edges with multiple fields are not supported by ent.
+			if childNode, ok := mutations[table.Name].(*schemast.UpsertSchema); ok {
+				text := fmt.Sprintf("entimport: foreign key %q (%s) referencing table %q is not imported, as ent does not support edges with multiple fields",
+					fk.Symbol, columnNames(fk.Columns), fk.RefTable.Name)
+				// Edges reference primary keys only, and the referenced unique index is noted for defining the relation manually.
+				if idx := refUniqueIndex(fk); idx != nil {
+					text = fmt.Sprintf("entimport: foreign key %q (%s) referencing the unique index %q (%s) of table %q is not imported, as ent does not support edges with multiple fields or edges referencing columns other than the id",
+						fk.Symbol, columnNames(fk.Columns), idx.Name, columnNames(fk.RefColumns), fk.RefTable.Name)
+				}
+				childNode.Annotations = append(childNode.Annotations, &commentAnnotation{
+					Method: "Edges",
+					Text:   text,
+				})
+			}
 			continue
 		}
 		parent := fk.RefTable
 		child := table
 		colName := fk.Columns[0].Name
+		if !i.includeColumn(child.Name, colName) {
+			continue
+		}
+		// Edges are named after the tables and the columns, and never by the symbols of the foreign keys,
+		// as symbols may be reused by different tables. The symbol is qualified by its column in the source.
 		opts := relOptions{
 			uniqueEdgeFromParent: true,
-			refName:              tableName(child.Name),
+			refName:              i.inflector().Plural(typeName(child.Name)),
 			edgeField:            colName,
+			edgeColumn:           colName,
+			source:               fmt.Sprintf("FK %s (%s.%s)", fk.Symbol, child.Name, colName),
+		}
+		if fk.Symbol == "" {
+			opts.source = fmt.Sprintf("column %s.%s (inferred)", child.Name, colName)
 		}
 		if child.Name == parent.Name {
 			opts.recursive = true
@@ -391,15 +2386,208 @@ func upsertOneToX(mutations map[string]schemast.Mutator, table *schema.Table) {
 		if ok && idx.Unique {
 			opts.uniqueEdgeToChild = true
 		}
+		if i.directives {
+			switch commentDirective(fk.Columns[0]) {
+			case "@o2o":
+				opts.uniqueEdgeToChild = true
+			case "@o2m":
+				opts.uniqueEdgeToChild = false
+			}
+		}
 		// If at least one table in the relation does not exist, there is no point to create it.
-		parentNode, ok := mutations[parent.Name].(*schemast.UpsertSchema)
+		childNode, ok := mutations[child.Name].(*schemast.UpsertSchema)
 		if !ok {
 			return
 		}
-		childNode, ok := mutations[child.Name].(*schemast.UpsertSchema)
+		// Edge fields are referenced by their names, which may differ from the column names.
+		if fld, ok := lookupField(childNode, colName); ok {
+			opts.edgeField = fld.Descriptor().Name
+			opts.comment = fld.Descriptor().Comment
+		}
+		parentNode, ok := mutations[parent.Name].(*schemast.UpsertSchema)
 		if !ok {
-			return
+			if i.stubMissingRefs {
+				stubEdge(i, childNode, parent, opts)
+			}
+			continue
+		}
+		alignEdgeField(i, parentNode, childNode, fk.Columns[0])
+		upsertRelation(i, parentNode, childNode, opts)
+	}
+}
+
+// commentDirective returns the last cardinality directive in the comment of the column, or an empty string.
+func commentDirective(c *schema.Column) (d string) {
+	for _, attr := range c.Attrs {
+		a, ok := attr.(*schema.Comment)
+		if !ok {
+			continue
+		}
+		for _, w := range strings.Fields(a.Text) {
+			if w = strings.ToLower(strings.TrimRight(w, ".,;")); w == "@o2o" || w == "@o2m" {
+				d = w
+			}
+		}
+	}
+	return d
+}
+
+// stubEdge adds a commented placeholder of the edge from the child node to a parent table that is not imported.
+func stubEdge(i *ImportOptions, childNode *schemast.UpsertSchema, parent *schema.Table, opts relOptions) {
+	parentType := i.typeNamePrefix + typeName(parent.Name)
+	// The edge is created on an empty node, as the fields of the child node are not changed for placeholders.
+	desc := entEdge(i, i.inflector().Plural(typeName(parent.Name)), parentType, &schemast.UpsertSchema{}, from, opts).Descriptor()
+	src := fmt.Sprintf("edge.From(%q, %s.Type).Ref(%q)", desc.Name, desc.Type, desc.RefName)
+	if desc.Unique {
+		src += ".Unique()"
+	}
+	if desc.Field != "" {
+		src += fmt.Sprintf(".Field(%q)", desc.Field)
+	}
+	childNode.Annotations = append(childNode.Annotations, &commentAnnotation{
+		Method: "Edges",
+		Text:   fmt.Sprintf("entimport: table %q is not imported, placeholder edge: %s", parent.Name, src),
+	})
+}
+
+// refUniqueIndex returns the unique index of the referenced table that is referenced by the foreign key, in case
+// the foreign key does not reference the primary key.
+func refUniqueIndex(fk *schema.ForeignKey) *schema.Index {
+	if fk.RefTable == nil || len(fk.RefColumns) == 0 || sameColumns(fk.RefTable.PrimaryKey, fk.RefColumns) {
+		return nil
+	}
+	for _, idx := range fk.RefTable.Indexes {
+		if idx.Unique && sameColumns(idx, fk.RefColumns) {
+			return idx
+		}
+	}
+	return nil
+}
+
+// sameColumns reports if the index parts are the given columns, in any order.
+func sameColumns(idx *schema.Index, columns []*schema.Column) bool {
+	if idx == nil || len(idx.Parts) != len(columns) {
+		return false
+	}
+	names := make(map[string]bool, len(columns))
+	for _, c := range columns {
+		names[c.Name] = true
+	}
+	for _, p := range idx.Parts {
+		if p.C == nil || !names[p.C.Name] {
+			return false
+		}
+	}
+	return true
+}
+
+// columnNames returns the comma-separated names of the given columns.
+func columnNames(columns []*schema.Column) string {
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = c.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// validateForeignKeys validates that the foreign keys of the tables reference the primary keys of imported tables,
+// and that the edge fields of the non-join tables are compatible with the ids they reference. Numeric fields
+// are compatible with numeric ids of any size or signedness, as they are aligned by alignEdgeField.
+func validateForeignKeys(i *ImportOptions, mutations map[string]schemast.Mutator, tables []*schema.Table) error {
+	imported := make(map[string]bool, len(tables))
+	for _, t := range tables {
+		imported[t.Name] = true
+	}
+	var errs []error
+	for _, table := range tables {
+		for _, fk := range table.ForeignKeys {
+			// Foreign keys with multiple columns, or of columns that are not imported, have no edges.
+			if len(fk.Columns) != 1 || len(fk.RefColumns) != 1 || !i.includeColumn(table.Name, fk.Columns[0].Name) {
+				continue
+			}
+			column, parent := fk.Columns[0].Name, fk.RefTable
+			switch {
+			case !imported[parent.Name]:
+				if !i.stubMissingRefs {
+					errs = append(errs, fmt.Errorf("entimport: foreign key %q of column %s.%s references table %q that is not imported",
+						fk.Symbol, table.Name, column, parent.Name))
+				}
+				continue
+			case !isPrimaryKey(parent, fk.RefColumns[0]):
+				errs = append(errs, fmt.Errorf("entimport: foreign key %q of column %s.%s references column %q of table %q that is not its primary key",
+					fk.Symbol, table.Name, column, fk.RefColumns[0].Name, parent.Name))
+				continue
+			}
+			childNode, ok := mutations[table.Name].(*schemast.UpsertSchema)
+			if !ok {
+				continue
+			}
+			parentNode, ok := mutations[parent.Name].(*schemast.UpsertSchema)
+			if !ok {
+				continue
+			}
+			fld, ok := lookupField(childNode, column)
+			if !ok {
+				continue
+			}
+			id, ok := idField(parentNode)
+			if !ok {
+				continue
+			}
+			fi, ii := fld.Descriptor().Info, id.Descriptor().Info
+			if fi.Numeric() && ii.Numeric() || fi.Type == ii.Type && fi.String() == ii.String() {
+				continue
+			}
+			errs = append(errs, fmt.Errorf("entimport: foreign key %q of column %s.%s of type %s references the primary key of table %q of type %s",
+				fk.Symbol, table.Name, column, fi, parent.Name, ii))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// alignEdgeField changes the type of the edge field to the type of the referenced id, in case both are numeric
+// and differ in their signedness or size, because ent requires the edge field and the id to be of the same type.
+// The column keeps its type in the database, as the schema type of the field.
+func alignEdgeField(i *ImportOptions, parentNode, childNode *schemast.UpsertSchema, column *schema.Column) {
+	id, ok := idField(parentNode)
+	if !ok {
+		return
+	}
+	fld, ok := lookupField(childNode, column.Name)
+	if !ok {
+		return
+	}
+	idDesc, fldDesc := id.Descriptor(), fld.Descriptor()
+	if !idDesc.Info.Numeric() || !fldDesc.Info.Numeric() || idDesc.Info.Type == fldDesc.Info.Type {
+		return
+	}
+	info := *idDesc.Info
+	fldDesc.Info = &info
+	if _, ok := fldDesc.SchemaType[i.driver.Dialect]; !ok && column.Type.Raw != "" {
+		if fldDesc.SchemaType == nil {
+			fldDesc.SchemaType = make(map[string]string)
+		}
+		fldDesc.SchemaType[i.driver.Dialect] = column.Type.Raw // Override the dialect.
+	}
+}
+
+// idField returns the id field of the given node. It is looked up by its name, as its storage key is the name of
+// the primary key column (e.g. "user_id"), and a non-key column named "id" is stored in an "id" column.
+func idField(node *schemast.UpsertSchema) (ent.Field, bool) {
+	for _, f := range node.Fields {
+		if f.Descriptor().Name == "id" {
+			return f, true
+		}
+	}
+	return nil, false
+}
+
+// lookupField returns the field of the given node by its name, or by the name of the column it is stored in.
+func lookupField(node *schemast.UpsertSchema, name string) (ent.Field, bool) {
+	for _, f := range node.Fields {
+		if d := f.Descriptor(); d.StorageKey == name || d.StorageKey == "" && d.Name == name {
+			return f, true
 		}
-		upsertRelation(parentNode, childNode, opts)
 	}
+	return nil, false
 }
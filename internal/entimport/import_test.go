@@ -1,18 +1,33 @@
 package entimport_test
 
 import (
+	"bytes"
 	"context"
 	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
 	"io/ioutil"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"ariga.io/atlas/sql/mysql"
 	"ariga.io/atlas/sql/postgres"
 	"ariga.io/atlas/sql/schema"
+	"ariga.io/entimport/internal/entimport"
 	"ariga.io/entimport/internal/mux"
 
+	"entgo.io/contrib/schemast"
+	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/entc/gen"
+	"entgo.io/ent/entc/load"
+	entschema "entgo.io/ent/schema"
+	"entgo.io/ent/schema/field"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -3975,6 +3990,1728 @@ func MockPostgresM2MJoinTableOnly() *schema.Schema {
 	}
 }
 
+func MockColumnWithoutType() *schema.Schema {
+	return mockTable("users",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "location",
+			Type: &schema.ColumnType{Raw: "geography"},
+		},
+	)
+}
+
+func MockPostgresOIDTypes() *schema.Schema {
+	return mockTable("catalogs",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "relid",
+			Type: &schema.ColumnType{Type: &schema.UnsupportedType{T: "oid"}, Raw: "oid"},
+		},
+		&schema.Column{
+			Name: "relation",
+			Type: &schema.ColumnType{Type: &schema.UnsupportedType{T: "regclass"}, Raw: "regclass", Null: true},
+		},
+	)
+}
+
+// mockTable returns a schema with a single table holding the given columns, where the first column is the primary key.
+func mockTable(name string, columns ...*schema.Column) *schema.Schema {
+	table := &schema.Table{
+		Name:    name,
+		Columns: columns,
+	}
+	table.PrimaryKey = &schema.Index{
+		Name:   "PRIMARY",
+		Unique: true,
+		Table:  table,
+		Parts:  []*schema.IndexPart{{C: columns[0]}},
+	}
+	return &schema.Schema{
+		Name:   "test",
+		Tables: []*schema.Table{table},
+	}
+}
+
+func MockMySQLO2MSignednessMismatch() *schema.Schema {
+	users := mockTable("users",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint", Unsigned: true}, Raw: "bigint unsigned"},
+		},
+		&schema.Column{
+			Name: "name",
+			Type: &schema.ColumnType{Type: &schema.StringType{T: "varchar", Size: 255}, Raw: "varchar(255)"},
+		},
+	).Tables[0]
+	pets := mockTable("pets",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "owner_id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "int"}, Raw: "int", Null: true},
+		},
+	).Tables[0]
+	pets.ForeignKeys = []*schema.ForeignKey{
+		{
+			Symbol:     "pets_owner_id",
+			Table:      pets,
+			Columns:    pets.Columns[1:],
+			RefTable:   users,
+			RefColumns: users.Columns[:1],
+		},
+	}
+	return &schema.Schema{
+		Name:   "test",
+		Tables: []*schema.Table{users, pets},
+	}
+}
+
+func MockPostgresBytesDefault() *schema.Schema {
+	bytea := func(name string, def schema.Expr) *schema.Column {
+		return &schema.Column{
+			Name:    name,
+			Type:    &schema.ColumnType{Type: &schema.BinaryType{T: "bytea"}, Raw: "bytea"},
+			Default: def,
+		}
+	}
+	return mockTable("files",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		bytea("header", &schema.Literal{V: `'\x00ff'`}),
+		bytea("magic", &schema.RawExpr{X: `'ab\001\\'::bytea`}),
+		bytea("digest", &schema.RawExpr{X: `decode('00', 'hex')`}),
+	)
+}
+
+func MockMySQLMixedSelfAndCrossFKs() *schema.Schema {
+	posts := mockTable("posts",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+	).Tables[0]
+	comments := mockTable("comments",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "parent_comment_id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint", Null: true},
+		},
+		&schema.Column{
+			Name: "post_id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint", Null: true},
+		},
+		&schema.Column{
+			Name: "root_comment_id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint", Null: true},
+		},
+	).Tables[0]
+	comments.ForeignKeys = []*schema.ForeignKey{
+		{
+			Symbol:     "comments_parent_comment_id",
+			Table:      comments,
+			Columns:    comments.Columns[1:2],
+			RefTable:   comments,
+			RefColumns: comments.Columns[:1],
+		},
+		{
+			Symbol:     "comments_post_id",
+			Table:      comments,
+			Columns:    comments.Columns[2:3],
+			RefTable:   posts,
+			RefColumns: posts.Columns[:1],
+		},
+		{
+			Symbol:     "comments_root_comment_id",
+			Table:      comments,
+			Columns:    comments.Columns[3:4],
+			RefTable:   comments,
+			RefColumns: comments.Columns[:1],
+		},
+	}
+	return &schema.Schema{
+		Name:   "test",
+		Tables: []*schema.Table{posts, comments},
+	}
+}
+
+func MockMySQLEnumFields() *schema.Schema {
+	return mockTable("orders",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "status",
+			Type: &schema.ColumnType{Type: &schema.EnumType{T: "enum", Values: []string{"pending", "shipped"}}, Raw: "enum('pending','shipped')"},
+		},
+		&schema.Column{
+			Name: "kind",
+			Type: &schema.ColumnType{Type: &schema.EnumType{T: "enum", Values: []string{"online", "store"}}, Raw: "enum('online','store')"},
+		},
+	)
+}
+
+func MockMySQLTinyIntBool() *schema.Schema {
+	return mockTable("settings",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "enabled",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "tinyint", Unsigned: true}, Raw: "tinyint(1) unsigned"},
+		},
+		&schema.Column{
+			Name: "level",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "tinyint", Unsigned: true}, Raw: "tinyint(3) unsigned"},
+		},
+	)
+}
+
+func MockPostgresIdentityGeneration() *schema.Schema {
+	identity := func(table, generation string) *schema.Table {
+		return mockTable(table,
+			&schema.Column{
+				Name:  "id",
+				Type:  &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+				Attrs: []schema.Attr{&postgres.Identity{Generation: generation}},
+			},
+		).Tables[0]
+	}
+	return &schema.Schema{
+		Name:   "test",
+		Tables: []*schema.Table{identity("events", "ALWAYS"), identity("logs", "BY DEFAULT")},
+	}
+}
+
+func MockMySQLCompositeForeignKey() *schema.Schema {
+	accounts := mockTable("accounts",
+		&schema.Column{
+			Name: "region",
+			Type: &schema.ColumnType{Type: &schema.StringType{T: "varchar", Size: 8}, Raw: "varchar(8)"},
+		},
+		&schema.Column{
+			Name: "number",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+	).Tables[0]
+	accounts.PrimaryKey.Parts = append(accounts.PrimaryKey.Parts, &schema.IndexPart{SeqNo: 1, C: accounts.Columns[1]})
+	transfers := mockTable("transfers",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "account_region",
+			Type: &schema.ColumnType{Type: &schema.StringType{T: "varchar", Size: 8}, Raw: "varchar(8)"},
+		},
+		&schema.Column{
+			Name: "account_number",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+	).Tables[0]
+	transfers.ForeignKeys = []*schema.ForeignKey{
+		{
+			Symbol:     "transfers_account",
+			Table:      transfers,
+			Columns:    transfers.Columns[1:],
+			RefTable:   accounts,
+			RefColumns: accounts.Columns,
+		},
+	}
+	return &schema.Schema{
+		Name:   "test",
+		Tables: []*schema.Table{accounts, transfers},
+	}
+}
+
+func MockMySQLMixedColumns() *schema.Schema {
+	users := mockTable("users",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+	).Tables[0]
+	posts := mockTable("posts",
+		&schema.Column{
+			Name: "author_id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint", Null: true},
+		},
+		&schema.Column{
+			Name: "title",
+			Type: &schema.ColumnType{Type: &schema.StringType{T: "varchar", Size: 255}, Raw: "varchar(255)"},
+		},
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "editor_id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint", Null: true},
+		},
+		&schema.Column{
+			Name: "body",
+			Type: &schema.ColumnType{Type: &schema.StringType{T: "text"}, Raw: "text"},
+		},
+	).Tables[0]
+	posts.PrimaryKey.Parts[0].C = posts.Columns[2]
+	posts.ForeignKeys = []*schema.ForeignKey{
+		{
+			Symbol:     "posts_author_id",
+			Table:      posts,
+			Columns:    posts.Columns[:1],
+			RefTable:   users,
+			RefColumns: users.Columns,
+		},
+		{
+			Symbol:     "posts_editor_id",
+			Table:      posts,
+			Columns:    posts.Columns[3:4],
+			RefTable:   users,
+			RefColumns: users.Columns,
+		},
+	}
+	return &schema.Schema{
+		Name:   "test",
+		Tables: []*schema.Table{users, posts},
+	}
+}
+
+func MockMySQLZerofill() *schema.Schema {
+	return mockTable("invoices",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "number",
+			Type: &schema.ColumnType{
+				Type: &schema.IntegerType{
+					T:        "int",
+					Unsigned: true,
+					Attrs:    []schema.Attr{&mysql.DisplayWidth{N: 10}, &mysql.ZeroFill{A: "zerofill"}},
+				},
+				Raw: "int(10) unsigned zerofill",
+			},
+		},
+		&schema.Column{
+			Name: "total",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "int", Unsigned: true}, Raw: "int unsigned"},
+		},
+	)
+}
+
+func MockPostgresLtree() *schema.Schema {
+	return mockTable("categories",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "path",
+			Type: &schema.ColumnType{Type: &postgres.UserDefinedType{T: "ltree"}, Raw: "USER-DEFINED"},
+		},
+	)
+}
+
+func MockMySQLEnumDefault() *schema.Schema {
+	return mockTable("orders",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name:    "status",
+			Type:    &schema.ColumnType{Type: &schema.EnumType{T: "enum", Values: []string{"shipped", "pending", "canceled"}}, Raw: "enum('shipped','pending','canceled')"},
+			Default: &schema.Literal{V: `"pending"`},
+		},
+		&schema.Column{
+			Name:    "priority",
+			Type:    &schema.ColumnType{Type: &schema.EnumType{T: "enum", Values: []string{"low", "high"}}, Raw: "enum('low','high')"},
+			Default: &schema.Literal{V: `"urgent"`},
+		},
+	)
+}
+
+func MockPostgresEnumDefault() *schema.Schema {
+	return mockTable("orders",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name:    "status",
+			Type:    &schema.ColumnType{Type: &schema.EnumType{T: "order_status", Values: []string{"shipped", "pending", "canceled"}}, Raw: "USER-DEFINED"},
+			Default: &schema.RawExpr{X: "'pending'::order_status"},
+		},
+		&schema.Column{
+			Name: "priority",
+			Type: &schema.ColumnType{Type: &schema.EnumType{T: "order_priority", Values: []string{"low", "high"}}, Raw: "USER-DEFINED", Null: true},
+		},
+	)
+}
+
+func MockMySQLKeywordColumns() *schema.Schema {
+	users := mockTable("users",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+	).Tables[0]
+	pets := mockTable("pets",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "type",
+			Type: &schema.ColumnType{Type: &schema.StringType{T: "varchar", Size: 255}, Raw: "varchar(255)"},
+		},
+		&schema.Column{
+			Name: "name",
+			Type: &schema.ColumnType{Type: &schema.StringType{T: "varchar", Size: 255}, Raw: "varchar(255)"},
+		},
+		&schema.Column{
+			Name: "go",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint", Null: true},
+		},
+	).Tables[0]
+	pets.ForeignKeys = []*schema.ForeignKey{
+		{
+			Symbol:     "pets_go",
+			Table:      pets,
+			Columns:    pets.Columns[3:],
+			RefTable:   users,
+			RefColumns: users.Columns,
+		},
+	}
+	return &schema.Schema{
+		Name:   "test",
+		Tables: []*schema.Table{users, pets},
+	}
+}
+
+func MockMySQLFKStorageKey() *schema.Schema {
+	users := mockTable("users",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+	).Tables[0]
+	pets := mockTable("pets",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "owner_ref",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint", Null: true},
+		},
+		&schema.Column{
+			Name: "vet_id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint", Null: true},
+		},
+	).Tables[0]
+	pets.ForeignKeys = []*schema.ForeignKey{
+		{
+			Symbol:     "pets_owner_ref",
+			Table:      pets,
+			Columns:    pets.Columns[1:2],
+			RefTable:   users,
+			RefColumns: users.Columns[:1],
+		},
+		{
+			Symbol:     "pets_vet_id",
+			Table:      pets,
+			Columns:    pets.Columns[2:3],
+			RefTable:   users,
+			RefColumns: users.Columns[:1],
+		},
+	}
+	return &schema.Schema{
+		Name:   "test",
+		Tables: []*schema.Table{users, pets},
+	}
+}
+
+func MockPostgresFixedChar() *schema.Schema {
+	return mockTable("countries",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "code",
+			Type: &schema.ColumnType{Type: &schema.StringType{T: "character", Size: 5}, Raw: "character"},
+		},
+		&schema.Column{
+			Name: "flag",
+			Type: &schema.ColumnType{Type: &schema.StringType{T: "character"}, Raw: "character"},
+		},
+		&schema.Column{
+			Name: "name",
+			Type: &schema.ColumnType{Type: &schema.StringType{T: "character varying", Size: 255}, Raw: "character varying"},
+		},
+	)
+}
+
+func MockMySQLUnsupportedColumns() *schema.Schema {
+	id := func() *schema.Column {
+		return &schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		}
+	}
+	places := mockTable("places",
+		id(),
+		&schema.Column{
+			Name: "location",
+			Type: &schema.ColumnType{Type: &schema.SpatialType{T: "point"}, Raw: "point"},
+		},
+	).Tables[0]
+	areas := mockTable("areas",
+		id(),
+		&schema.Column{
+			Name: "shape",
+			Type: &schema.ColumnType{Type: &schema.SpatialType{T: "polygon"}, Raw: "polygon"},
+		},
+	).Tables[0]
+	users := mockTable("users", id()).Tables[0]
+	return &schema.Schema{
+		Name:   "test",
+		Tables: []*schema.Table{places, areas, users},
+	}
+}
+
+func MockMySQLTimestamps() *schema.Schema {
+	timestamp := func(name string) *schema.Column {
+		return &schema.Column{
+			Name: name,
+			Type: &schema.ColumnType{Type: &schema.TimeType{T: "timestamp"}, Raw: "timestamp"},
+		}
+	}
+	return mockTable("posts",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		timestamp("created_at"),
+		timestamp("updated_at"),
+	)
+}
+
+func MockPostgresUUIDDefault() *schema.Schema {
+	return mockTable("accounts",
+		&schema.Column{
+			Name:    "id",
+			Type:    &schema.ColumnType{Type: &postgres.UUIDType{T: "uuid"}, Raw: "uuid"},
+			Default: &schema.RawExpr{X: "gen_random_uuid()"},
+		},
+		&schema.Column{
+			Name:    "token",
+			Type:    &schema.ColumnType{Type: &postgres.UUIDType{T: "uuid"}, Raw: "uuid"},
+			Default: &schema.RawExpr{X: "uuid_generate_v4()"},
+		},
+		&schema.Column{
+			Name: "external_id",
+			Type: &schema.ColumnType{Type: &postgres.UUIDType{T: "uuid"}, Raw: "uuid", Null: true},
+		},
+	)
+}
+
+func MockMySQLEnumSpaces() *schema.Schema {
+	return mockTable("tasks",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name:    "state",
+			Type:    &schema.ColumnType{Type: &schema.EnumType{T: "enum", Values: []string{" value ", "value", "in progress", "done"}}, Raw: "enum(' value ','value','in progress','done')"},
+			Default: &schema.Literal{V: `"in progress"`},
+		},
+	)
+}
+
+func MockMySQLFKColumnNamedAsEdge() *schema.Schema {
+	users := mockTable("users",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+	).Tables[0]
+	posts := mockTable("posts",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "user",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint", Null: true},
+		},
+		&schema.Column{
+			Name: "user_id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+	).Tables[0]
+	posts.ForeignKeys = []*schema.ForeignKey{
+		{
+			Symbol:     "posts_user",
+			Table:      posts,
+			Columns:    posts.Columns[1:2],
+			RefTable:   users,
+			RefColumns: users.Columns[:1],
+		},
+	}
+	return &schema.Schema{
+		Name:   "test",
+		Tables: []*schema.Table{users, posts},
+	}
+}
+
+func MockPostgresUnboundedNumeric() *schema.Schema {
+	return mockTable("balances",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "amount",
+			Type: &schema.ColumnType{Type: &schema.DecimalType{T: "numeric"}, Raw: "numeric"},
+		},
+		&schema.Column{
+			Name: "rate",
+			Type: &schema.ColumnType{Type: &schema.DecimalType{T: "numeric", Precision: 10, Scale: 2}, Raw: "numeric"},
+		},
+	)
+}
+
+func MockPostgresTimePrecision() *schema.Schema {
+	return mockTable("events",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "created_at",
+			Type: &schema.ColumnType{Type: &schema.TimeType{T: "timestamp without time zone", Precision: 6}, Raw: "timestamp without time zone"},
+		},
+		&schema.Column{
+			Name: "seen_at",
+			Type: &schema.ColumnType{Type: &schema.TimeType{T: "timestamp with time zone", Precision: 3}, Raw: "timestamp with time zone"},
+		},
+		&schema.Column{
+			Name: "day",
+			Type: &schema.ColumnType{Type: &schema.TimeType{T: "date"}, Raw: "date"},
+		},
+		&schema.Column{
+			Name: "opens_at",
+			Type: &schema.ColumnType{Type: &schema.TimeType{T: "time with time zone"}, Raw: "time with time zone"},
+		},
+		&schema.Column{
+			Name: "closes_at",
+			Type: &schema.ColumnType{Type: &schema.TimeType{T: "timetz", Precision: 3}, Raw: "timetz"},
+		},
+		&schema.Column{
+			Name: "reminder",
+			Type: &schema.ColumnType{Type: &schema.TimeType{T: "time without time zone"}, Raw: "time without time zone"},
+		},
+	)
+}
+
+func MockMySQLUnsafeTableNames() *schema.Schema {
+	users := mockTable("user data",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+	).Tables[0]
+	items := mockTable("order-items",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "user_id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint", Null: true},
+		},
+	).Tables[0]
+	items.ForeignKeys = []*schema.ForeignKey{
+		{
+			Symbol:     "order_items_user_id",
+			Table:      items,
+			Columns:    items.Columns[1:],
+			RefTable:   users,
+			RefColumns: users.Columns[:1],
+		},
+	}
+	codes := mockTable("2fa.codes",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+	).Tables[0]
+	return &schema.Schema{
+		Name:   "test",
+		Tables: []*schema.Table{users, items, codes},
+	}
+}
+
+func MockMySQLEmptyPrimaryKey() *schema.Schema {
+	s := mockTable("logs",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+	)
+	s.Tables[0].PrimaryKey.Parts = nil
+	return s
+}
+
+func MockMySQLCounters() *schema.Schema {
+	bigint := func(name string) *schema.Column {
+		return &schema.Column{
+			Name: name,
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		}
+	}
+	return mockTable("stats", bigint("id"), bigint("views"), bigint("likes"))
+}
+
+func MockPostgresJSONTypes() *schema.Schema {
+	return mockTable("documents",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "raw",
+			Type: &schema.ColumnType{Type: &schema.JSONType{T: "json"}, Raw: "json"},
+		},
+		&schema.Column{
+			Name: "data",
+			Type: &schema.ColumnType{Type: &schema.JSONType{T: "jsonb"}, Raw: "jsonb", Null: true},
+		},
+	)
+}
+
+func MockPostgresOpaqueTypes() *schema.Schema {
+	return mockTable("replication_slots",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "lsn",
+			Type: &schema.ColumnType{Type: &schema.UnsupportedType{T: "pg_lsn"}, Raw: "pg_lsn"},
+		},
+		&schema.Column{
+			Name: "snapshot",
+			Type: &schema.ColumnType{Type: &schema.UnsupportedType{T: "txid_snapshot"}, Raw: "txid_snapshot", Null: true},
+		},
+	)
+}
+
+func MockMySQLTimestampDefaults() *schema.Schema {
+	return mockTable("sessions",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name:    "created_at",
+			Type:    &schema.ColumnType{Type: &schema.TimeType{T: "timestamp"}, Raw: "timestamp"},
+			Default: &schema.RawExpr{X: "CURRENT_TIMESTAMP"},
+		},
+		&schema.Column{
+			Name:    "expired_at",
+			Type:    &schema.ColumnType{Type: &schema.TimeType{T: "timestamp"}, Raw: "timestamp", Null: true},
+			Default: &schema.RawExpr{X: "CURRENT_TIMESTAMP"},
+		},
+	)
+}
+
+func MockMySQLLookupTableWithoutFK() *schema.Schema {
+	categories := mockTable("categories",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "name",
+			Type: &schema.ColumnType{Type: &schema.StringType{T: "varchar", Size: 255}, Raw: "varchar(255)"},
+		},
+	).Tables[0]
+	orders := mockTable("orders",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "category_id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "tracking_id",
+			Type: &schema.ColumnType{Type: &schema.StringType{T: "varchar", Size: 255}, Raw: "varchar(255)"},
+		},
+	).Tables[0]
+	return &schema.Schema{
+		Name:   "test",
+		Tables: []*schema.Table{categories, orders},
+	}
+}
+
+func MockMySQLNullableUnique() *schema.Schema {
+	s := mockTable("accounts",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "email",
+			Type: &schema.ColumnType{Type: &schema.StringType{T: "varchar", Size: 255}, Raw: "varchar(255)", Null: true},
+		},
+		&schema.Column{
+			Name: "name",
+			Type: &schema.ColumnType{Type: &schema.StringType{T: "varchar", Size: 255}, Raw: "varchar(255)"},
+		},
+	)
+	table := s.Tables[0]
+	table.Indexes = []*schema.Index{
+		{Name: "email", Unique: true, Table: table, Parts: []*schema.IndexPart{{C: table.Columns[1]}}},
+		{Name: "lower_name", Unique: true, Table: table, Parts: []*schema.IndexPart{{X: &schema.RawExpr{X: "lower(`name`)"}}}},
+	}
+	return s
+}
+
+func MockPostgresStringArrays() *schema.Schema {
+	return mockTable("posts",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "tags",
+			Type: &schema.ColumnType{Type: &postgres.ArrayType{T: "varchar(255)[]"}, Raw: "ARRAY"},
+		},
+		&schema.Column{
+			Name: "notes",
+			Type: &schema.ColumnType{Type: &postgres.ArrayType{T: "text[]"}, Raw: "ARRAY", Null: true},
+		},
+		&schema.Column{
+			Name: "scores",
+			Type: &schema.ColumnType{Type: &postgres.ArrayType{T: "integer[]"}, Raw: "ARRAY"},
+		},
+	)
+}
+
+func MockMySQLDecimalPK() *schema.Schema {
+	accounts := mockTable("accounts",
+		&schema.Column{
+			Name: "number",
+			Type: &schema.ColumnType{Type: &schema.DecimalType{T: "decimal", Precision: 20, Unsigned: true}, Raw: "decimal(20,0) unsigned"},
+		},
+		&schema.Column{
+			Name: "balance",
+			Type: &schema.ColumnType{Type: &schema.DecimalType{T: "decimal", Precision: 10, Scale: 2}, Raw: "decimal(10,2)"},
+		},
+	).Tables[0]
+	transfers := mockTable("transfers",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "account_number",
+			Type: &schema.ColumnType{Type: &schema.DecimalType{T: "decimal", Precision: 20, Unsigned: true}, Raw: "decimal(20,0) unsigned", Null: true},
+		},
+	).Tables[0]
+	transfers.ForeignKeys = []*schema.ForeignKey{
+		{
+			Symbol:     "transfers_account_number",
+			Table:      transfers,
+			Columns:    transfers.Columns[1:2],
+			RefTable:   accounts,
+			RefColumns: accounts.Columns[:1],
+		},
+	}
+	return &schema.Schema{
+		Name:   "test",
+		Tables: []*schema.Table{accounts, transfers},
+	}
+}
+
+func MockMySQLDefaultFuncs() *schema.Schema {
+	return mockTable("tokens",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name:    "value",
+			Type:    &schema.ColumnType{Type: &schema.StringType{T: "varchar", Size: 36}, Raw: "varchar(36)"},
+			Default: &schema.RawExpr{X: "(uuid())"},
+		},
+		&schema.Column{
+			Name:    "issued_at",
+			Type:    &schema.ColumnType{Type: &schema.TimeType{T: "datetime"}, Raw: "datetime(6)"},
+			Default: &schema.RawExpr{X: "CURRENT_TIMESTAMP(6)"},
+		},
+		&schema.Column{
+			Name:    "seed",
+			Type:    &schema.ColumnType{Type: &schema.FloatType{T: "double"}, Raw: "double"},
+			Default: &schema.RawExpr{X: "(rand())"},
+		},
+		&schema.Column{
+			Name:    "kind",
+			Type:    &schema.ColumnType{Type: &schema.StringType{T: "varchar", Size: 255}, Raw: "varchar(255)"},
+			Default: &schema.Literal{V: `"basic"`},
+		},
+	)
+}
+
+func MockPostgresDefaultFuncs() *schema.Schema {
+	return mockTable("events",
+		&schema.Column{
+			Name:    "id",
+			Type:    &schema.ColumnType{Type: &postgres.UUIDType{T: "uuid"}, Raw: "uuid"},
+			Default: &schema.RawExpr{X: "gen_random_uuid()"},
+		},
+		&schema.Column{
+			Name:    "ref",
+			Type:    &schema.ColumnType{Type: &postgres.UUIDType{T: "uuid"}, Raw: "uuid"},
+			Default: &schema.RawExpr{X: "uuid_generate_v4()"},
+		},
+		&schema.Column{
+			Name:    "created_at",
+			Type:    &schema.ColumnType{Type: &schema.TimeType{T: "timestamp with time zone"}, Raw: "timestamp with time zone"},
+			Default: &schema.RawExpr{X: "now()"},
+		},
+		&schema.Column{
+			Name:    "updated_at",
+			Type:    &schema.ColumnType{Type: &schema.TimeType{T: "timestamp without time zone"}, Raw: "timestamp without time zone"},
+			Default: &schema.RawExpr{X: "CURRENT_TIMESTAMP"},
+		},
+		&schema.Column{
+			Name:    "local_at",
+			Type:    &schema.ColumnType{Type: &schema.TimeType{T: "timestamp without time zone"}, Raw: "timestamp without time zone"},
+			Default: &schema.RawExpr{X: "LOCALTIMESTAMP"},
+		},
+		&schema.Column{
+			Name:    "seq",
+			Type:    &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+			Default: &schema.RawExpr{X: "nextval('events_seq'::regclass)"},
+		},
+		&schema.Column{
+			Name:    "digest",
+			Type:    &schema.ColumnType{Type: &schema.StringType{T: "text"}, Raw: "text"},
+			Default: &schema.RawExpr{X: "md5(random()::text)"},
+		},
+	)
+}
+
+func MockMySQLReservedNames() *schema.Schema {
+	return mockTable("graphs",
+		&schema.Column{
+			Name: "graph_id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.StringType{T: "varchar", Size: 255}, Raw: "varchar(255)"},
+		},
+		&schema.Column{
+			Name: "edges",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "int"}, Raw: "int"},
+		},
+		&schema.Column{
+			Name: "String",
+			Type: &schema.ColumnType{Type: &schema.StringType{T: "varchar", Size: 255}, Raw: "varchar(255)"},
+		},
+		&schema.Column{
+			Name: "nodes",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "int"}, Raw: "int"},
+		},
+	)
+}
+
+func MockMySQLFKToCompositeUnique() *schema.Schema {
+	accounts := mockTable("accounts",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "region",
+			Type: &schema.ColumnType{Type: &schema.StringType{T: "varchar", Size: 8}, Raw: "varchar(8)"},
+		},
+		&schema.Column{
+			Name: "number",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+	).Tables[0]
+	accounts.Indexes = []*schema.Index{
+		{
+			Name:   "accounts_region_number",
+			Unique: true,
+			Table:  accounts,
+			Parts:  []*schema.IndexPart{{C: accounts.Columns[1]}, {SeqNo: 1, C: accounts.Columns[2]}},
+		},
+	}
+	transfers := mockTable("transfers",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "account_region",
+			Type: &schema.ColumnType{Type: &schema.StringType{T: "varchar", Size: 8}, Raw: "varchar(8)"},
+		},
+		&schema.Column{
+			Name: "account_number",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+	).Tables[0]
+	transfers.ForeignKeys = []*schema.ForeignKey{
+		{
+			Symbol:     "transfers_account",
+			Table:      transfers,
+			Columns:    transfers.Columns[1:],
+			RefTable:   accounts,
+			RefColumns: accounts.Columns[1:],
+		},
+	}
+	return &schema.Schema{
+		Name:   "test",
+		Tables: []*schema.Table{accounts, transfers},
+	}
+}
+
+func MockMySQLTextTypes() *schema.Schema {
+	text := func(name, typ string) *schema.Column {
+		return &schema.Column{
+			Name: name,
+			Type: &schema.ColumnType{Type: &schema.StringType{T: typ}, Raw: typ},
+		}
+	}
+	return mockTable("articles",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		text("summary", "tinytext"),
+		text("intro", "text"),
+		text("body", "mediumtext"),
+		text("raw", "longtext"),
+		&schema.Column{
+			Name: "title",
+			Type: &schema.ColumnType{Type: &schema.StringType{T: "varchar", Size: 255}, Raw: "varchar(255)"},
+		},
+	)
+}
+
+func MockMySQLReusedFKSymbols() *schema.Schema {
+	id := func() *schema.Column {
+		return &schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		}
+	}
+	ref := func(name string) *schema.Column {
+		return &schema.Column{
+			Name: name,
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint", Null: true},
+		}
+	}
+	users := mockTable("users", id()).Tables[0]
+	posts := mockTable("posts", id(), ref("user_id")).Tables[0]
+	comments := mockTable("comments", id(), ref("user_id"), ref("post_id")).Tables[0]
+	// The same symbols are used by the foreign keys of different tables.
+	posts.ForeignKeys = []*schema.ForeignKey{
+		{Symbol: "fk_user", Table: posts, Columns: posts.Columns[1:2], RefTable: users, RefColumns: users.Columns},
+	}
+	comments.ForeignKeys = []*schema.ForeignKey{
+		{Symbol: "fk_user", Table: comments, Columns: comments.Columns[1:2], RefTable: users, RefColumns: users.Columns},
+		{Symbol: "fk_post", Table: comments, Columns: comments.Columns[2:3], RefTable: posts, RefColumns: posts.Columns[:1]},
+	}
+	return &schema.Schema{
+		Name:   "test",
+		Tables: []*schema.Table{users, posts, comments},
+	}
+}
+
+func MockMySQLIntEnums() *schema.Schema {
+	return mockTable("tickets",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "priority",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "tinyint"}, Raw: "tinyint(4)", Null: true},
+		},
+		&schema.Column{
+			Name: "state",
+			Type: &schema.ColumnType{Type: &schema.StringType{T: "varchar", Size: 16}, Raw: "varchar(16)"},
+		},
+		&schema.Column{
+			Name: "score",
+			Type: &schema.ColumnType{Type: &schema.FloatType{T: "double"}, Raw: "double"},
+		},
+	)
+}
+
+func MockPostgresInherits() *schema.Schema {
+	vehicles := mockTable("vehicles",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "make",
+			Type: &schema.ColumnType{Type: &schema.StringType{T: "text"}, Raw: "text"},
+		},
+		&schema.Column{
+			Name: "year",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "integer"}, Raw: "integer", Null: true},
+		},
+	).Tables[0]
+	cars := mockTable("cars",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "make",
+			Type: &schema.ColumnType{Type: &schema.StringType{T: "text"}, Raw: "text"},
+		},
+		&schema.Column{
+			Name: "year",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "integer"}, Raw: "integer", Null: true},
+		},
+		&schema.Column{
+			Name: "doors",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "smallint"}, Raw: "smallint"},
+		},
+	).Tables[0]
+	cars.Attrs = []schema.Attr{&entimport.Inherits{Parent: "vehicles"}}
+	trucks := mockTable("trucks",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "make",
+			Type: &schema.ColumnType{Type: &schema.StringType{T: "text"}, Raw: "text"},
+		},
+		&schema.Column{
+			Name: "year",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "integer"}, Raw: "integer", Null: true},
+		},
+		&schema.Column{
+			Name: "payload",
+			Type: &schema.ColumnType{Type: &schema.FloatType{T: "double precision"}, Raw: "double precision"},
+		},
+	).Tables[0]
+	trucks.Attrs = []schema.Attr{&entimport.Inherits{Parent: "vehicles"}}
+	return &schema.Schema{
+		Name:   "test",
+		Tables: []*schema.Table{vehicles, cars, trucks},
+	}
+}
+
+func MockMySQLBinary16() *schema.Schema {
+	return mockTable("sessions",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.BinaryType{T: "binary", Size: 16}, Raw: "binary(16)"},
+		},
+		&schema.Column{
+			Name: "user_id",
+			Type: &schema.ColumnType{Type: &schema.BinaryType{T: "binary", Size: 16}, Raw: "binary(16)", Null: true},
+		},
+		&schema.Column{
+			Name: "digest",
+			Type: &schema.ColumnType{Type: &schema.BinaryType{T: "binary", Size: 32}, Raw: "binary(32)"},
+		},
+		&schema.Column{
+			Name: "nonce",
+			Type: &schema.ColumnType{Type: &schema.BinaryType{T: "varbinary", Size: 16}, Raw: "varbinary(16)"},
+		},
+	)
+}
+
+func MockMySQLCommentDirectives() *schema.Schema {
+	users := mockTable("users",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+	).Tables[0]
+	accounts := mockTable("accounts",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name:  "user_id",
+			Type:  &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint", Null: true},
+			Attrs: []schema.Attr{&schema.Comment{Text: "the owner of the account @o2o"}},
+		},
+	).Tables[0]
+	accounts.ForeignKeys = []*schema.ForeignKey{
+		{
+			Symbol:     "accounts_user_id",
+			Table:      accounts,
+			Columns:    accounts.Columns[1:2],
+			RefTable:   users,
+			RefColumns: users.Columns[:1],
+		},
+	}
+	devices := mockTable("devices",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name:  "user_id",
+			Type:  &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint", Null: true},
+			Attrs: []schema.Attr{&schema.Comment{Text: "@O2M, the unique index is dropped in the next release"}},
+		},
+	).Tables[0]
+	devices.Indexes = []*schema.Index{
+		{Name: "devices_user_id", Unique: true, Table: devices, Parts: []*schema.IndexPart{{C: devices.Columns[1]}}},
+	}
+	devices.ForeignKeys = []*schema.ForeignKey{
+		{
+			Symbol:     "devices_user_id",
+			Table:      devices,
+			Columns:    devices.Columns[1:2],
+			RefTable:   users,
+			RefColumns: users.Columns[:1],
+		},
+	}
+	return &schema.Schema{
+		Name:   "test",
+		Tables: []*schema.Table{users, accounts, devices},
+	}
+}
+
+func MockPostgresBoolDefaults() *schema.Schema {
+	return mockTable("settings",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name:    "active",
+			Type:    &schema.ColumnType{Type: &schema.BoolType{T: "boolean"}, Raw: "boolean"},
+			Default: &schema.Literal{V: "true"},
+		},
+		&schema.Column{
+			Name:    "deleted",
+			Type:    &schema.ColumnType{Type: &schema.BoolType{T: "boolean"}, Raw: "boolean"},
+			Default: &schema.Literal{V: "false"},
+		},
+		&schema.Column{
+			Name:    "verified",
+			Type:    &schema.ColumnType{Type: &schema.BoolType{T: "boolean"}, Raw: "boolean"},
+			Default: &schema.RawExpr{X: "'t'::boolean"},
+		},
+		&schema.Column{
+			Name:    "locked",
+			Type:    &schema.ColumnType{Type: &schema.BoolType{T: "boolean"}, Raw: "boolean"},
+			Default: &schema.RawExpr{X: "'f'::boolean"},
+		},
+		&schema.Column{
+			Name:    "visible",
+			Type:    &schema.ColumnType{Type: &schema.BoolType{T: "boolean"}, Raw: "boolean"},
+			Default: &schema.Literal{V: "'1'"},
+		},
+		&schema.Column{
+			Name:    "archived",
+			Type:    &schema.ColumnType{Type: &schema.BoolType{T: "boolean"}, Raw: "boolean"},
+			Default: &schema.Literal{V: "'0'"},
+		},
+		&schema.Column{
+			Name:    "public",
+			Type:    &schema.ColumnType{Type: &schema.BoolType{T: "boolean"}, Raw: "boolean"},
+			Default: &schema.RawExpr{X: "'YES'::bool"},
+		},
+		&schema.Column{
+			Name:    "muted",
+			Type:    &schema.ColumnType{Type: &schema.BoolType{T: "boolean"}, Raw: "boolean"},
+			Default: &schema.RawExpr{X: "'off'"},
+		},
+		&schema.Column{
+			Name:    "flagged",
+			Type:    &schema.ColumnType{Type: &schema.BoolType{T: "boolean"}, Raw: "boolean"},
+			Default: &schema.RawExpr{X: "NULL::boolean"},
+		},
+		&schema.Column{
+			Name:    "computed",
+			Type:    &schema.ColumnType{Type: &schema.BoolType{T: "boolean"}, Raw: "boolean"},
+			Default: &schema.RawExpr{X: "is_admin()"},
+		},
+		&schema.Column{
+			Name: "plain",
+			Type: &schema.ColumnType{Type: &schema.BoolType{T: "boolean"}, Raw: "boolean"},
+		},
+	)
+}
+
+func MockMySQLCompositeIndexes() *schema.Schema {
+	s := mockTable("members",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "tenant_id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "email",
+			Type: &schema.ColumnType{Type: &schema.StringType{T: "varchar", Size: 255}, Raw: "varchar(255)"},
+		},
+		&schema.Column{
+			Name: "edges",
+			Type: &schema.ColumnType{Type: &schema.StringType{T: "varchar", Size: 255}, Raw: "varchar(255)"},
+		},
+	)
+	t := s.Tables[0]
+	t.Indexes = []*schema.Index{
+		{Name: "PRIMARY", Unique: true, Table: t, Parts: t.PrimaryKey.Parts},
+		{Name: "email", Unique: true, Table: t, Parts: []*schema.IndexPart{{C: t.Columns[2]}}},
+		{Name: "tenant_email", Unique: true, Table: t, Parts: []*schema.IndexPart{{C: t.Columns[1]}, {C: t.Columns[2]}}},
+		{Name: "tenant_edges", Table: t, Parts: []*schema.IndexPart{{C: t.Columns[1]}, {C: t.Columns[3]}}},
+		{Name: "tenant_lower_email", Table: t, Parts: []*schema.IndexPart{{C: t.Columns[1]}, {X: &schema.RawExpr{X: "lower(email)"}}}},
+	}
+	return s
+}
+
+func MockMySQLSharedTimestamps() *schema.Schema {
+	table := func(name string, nullUpdate bool) *schema.Table {
+		return mockTable(name,
+			&schema.Column{
+				Name: "id",
+				Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+			},
+			&schema.Column{
+				Name: "name",
+				Type: &schema.ColumnType{Type: &schema.StringType{T: "varchar", Size: 255}, Raw: "varchar(255)"},
+			},
+			&schema.Column{
+				Name: "created_at",
+				Type: &schema.ColumnType{Type: &schema.TimeType{T: "timestamp"}, Raw: "timestamp"},
+			},
+			&schema.Column{
+				Name: "updated_at",
+				Type: &schema.ColumnType{Type: &schema.TimeType{T: "timestamp"}, Raw: "timestamp", Null: nullUpdate},
+			},
+		).Tables[0]
+	}
+	return &schema.Schema{
+		Name:   "test",
+		Tables: []*schema.Table{table("users", false), table("groups", false), table("logs", true)},
+	}
+}
+
+func MockMySQLGeneratedJSON() *schema.Schema {
+	return mockTable("products",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "payload",
+			Type: &schema.ColumnType{Type: &schema.JSONType{T: "json"}, Raw: "json"},
+		},
+		&schema.Column{
+			Name:  "price",
+			Type:  &schema.ColumnType{Type: &schema.DecimalType{T: "decimal", Precision: 10, Scale: 2}, Raw: "decimal(10,2)"},
+			Attrs: []schema.Attr{&entimport.Generated{Expr: "json_extract(`payload`,_utf8mb4'$.price')", Stored: true}},
+		},
+		&schema.Column{
+			Name:  "name",
+			Type:  &schema.ColumnType{Type: &schema.StringType{T: "varchar", Size: 255}, Raw: "varchar(255)", Null: true},
+			Attrs: []schema.Attr{&entimport.Generated{Expr: "json_unquote(json_extract(`payload`,_utf8mb4'$.translated.name'))"}},
+		},
+		&schema.Column{
+			Name:  "total",
+			Type:  &schema.ColumnType{Type: &schema.IntegerType{T: "int"}, Raw: "int"},
+			Attrs: []schema.Attr{&entimport.Generated{Expr: "(`id` * 2)"}},
+		},
+	)
+}
+
+func MockMySQLIDColumn() *schema.Schema {
+	return mockTable("accounts",
+		&schema.Column{
+			Name: "uid",
+			Type: &schema.ColumnType{Type: &schema.StringType{T: "char", Size: 36}, Raw: "char(36)"},
+		},
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "id_field",
+			Type: &schema.ColumnType{Type: &schema.StringType{T: "varchar", Size: 255}, Raw: "varchar(255)"},
+		},
+	)
+}
+
+func MockPostgresArrayDefaults() *schema.Schema {
+	column := func(name string, def schema.Expr) *schema.Column {
+		return &schema.Column{
+			Name:    name,
+			Type:    &schema.ColumnType{Type: &postgres.ArrayType{T: "text[]"}, Raw: "ARRAY"},
+			Default: def,
+		}
+	}
+	return mockTable("posts",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "integer"}, Raw: "integer"},
+		},
+		column("tags", &schema.Literal{V: "'{a,b}'"}),
+		column("labels", &schema.Literal{V: "'{}'"}),
+		&schema.Column{
+			Name:    "names",
+			Type:    &schema.ColumnType{Type: &postgres.ArrayType{T: "varchar(255)[]"}, Raw: "ARRAY"},
+			Default: &schema.RawExpr{X: `'{"x y","it''s","q\"z"}'::character varying[]`},
+		},
+		column("kinds", &schema.RawExpr{X: "ARRAY['a'::text, 'b,c'::text]"}),
+		column("codes", &schema.RawExpr{X: "(ARRAY['x'::character varying])::character varying[]"}),
+		column("optional", &schema.Literal{V: "'{a,NULL}'"}),
+	)
+}
+
+func MockMySQLSetColumns() *schema.Schema {
+	return mockTable("members",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name:    "permissions",
+			Type:    &schema.ColumnType{Type: &mysql.SetType{Values: []string{"read", "write", "admin"}}, Raw: "set('read','write','admin')"},
+			Default: &schema.Literal{V: "'read,write'"},
+		},
+		&schema.Column{
+			Name: "flags",
+			Type: &schema.ColumnType{Type: &mysql.SetType{Values: []string{"a", "it's"}}, Null: true},
+		},
+	)
+}
+
+func MockPostgresNumericPrecision() *schema.Schema {
+	return mockTable("invoices",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "quantity",
+			Type: &schema.ColumnType{Type: &schema.DecimalType{T: "numeric", Precision: 5}, Raw: "numeric"},
+		},
+		&schema.Column{
+			Name: "serial",
+			Type: &schema.ColumnType{Type: &schema.DecimalType{T: "numeric", Precision: 18}, Raw: "numeric"},
+		},
+		&schema.Column{
+			Name: "big_serial",
+			Type: &schema.ColumnType{Type: &schema.DecimalType{T: "numeric", Precision: 30}, Raw: "numeric"},
+		},
+		&schema.Column{
+			Name: "total",
+			Type: &schema.ColumnType{Type: &schema.DecimalType{T: "numeric", Precision: 38, Scale: 10}, Raw: "numeric"},
+		},
+	)
+}
+
+func MockMySQLDecimalPrecision() *schema.Schema {
+	return mockTable("invoices",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "quantity",
+			Type: &schema.ColumnType{Type: &schema.DecimalType{T: "decimal", Precision: 5, Unsigned: true}, Raw: "decimal(5,0) unsigned"},
+		},
+		&schema.Column{
+			Name: "serial",
+			Type: &schema.ColumnType{Type: &schema.DecimalType{T: "decimal", Precision: 12}, Raw: "decimal(12,0)"},
+		},
+		&schema.Column{
+			Name: "total",
+			Type: &schema.ColumnType{Type: &schema.DecimalType{T: "decimal", Precision: 38, Scale: 10}, Raw: "decimal(38,10)"},
+		},
+	)
+}
+
+func MockPostgresUUIDArrays() *schema.Schema {
+	column := func(name string, def schema.Expr) *schema.Column {
+		return &schema.Column{
+			Name:    name,
+			Type:    &schema.ColumnType{Type: &postgres.ArrayType{T: "uuid[]"}, Raw: "ARRAY"},
+			Default: def,
+		}
+	}
+	return mockTable("groups",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "integer"}, Raw: "integer"},
+		},
+		column("members", nil),
+		column("owners", &schema.Literal{V: "'{}'"}),
+		column("admins", &schema.RawExpr{X: "'{6ba7b810-9dad-11d1-80b4-00c04fd430c8}'::uuid[]"}),
+		column("invalid", &schema.Literal{V: "'{x}'"}),
+	)
+}
+
+func MockMySQLCamelCaseColumns() *schema.Schema {
+	column := func(name string) *schema.Column {
+		return &schema.Column{
+			Name: name,
+			Type: &schema.ColumnType{Type: &schema.StringType{T: "varchar", Size: 255}, Raw: "varchar(255)"},
+		}
+	}
+	return mockTable("accounts",
+		&schema.Column{
+			Name: "accountID",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		column("userName"),
+		column("IsActive"),
+		column("HTTPCode"),
+		column("address2Line"),
+		column("createdAt"),
+		column("created_at"),
+		column("plain"),
+	)
+}
+
+func MockPostgresArrays() *schema.Schema {
+	column := func(name, typ string, def schema.Expr) *schema.Column {
+		return &schema.Column{
+			Name:    name,
+			Type:    &schema.ColumnType{Type: &postgres.ArrayType{T: typ}, Raw: "ARRAY"},
+			Default: def,
+		}
+	}
+	return mockTable("measurements",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		column("counts", "int4[]", &schema.Literal{V: "'{1,2}'"}),
+		column("totals", "bigint[]", &schema.RawExpr{X: "ARRAY[3, '-4'::bigint]"}),
+		column("ratios", "float8[]", &schema.RawExpr{X: "ARRAY[0.5, 1]"}),
+		column("flags", "bool[]", &schema.Literal{V: "'{t,false}'"}),
+		column("matrix", "integer[][]", &schema.RawExpr{X: "'{{1,2},{3,4}}'::integer[]"}),
+		column("invalid", "int4[]", &schema.RawExpr{X: "'{1.5}'::integer[]"}),
+	)
+}
+
+func MockMySQLForeignKeyMismatch() *schema.Schema {
+	users := mockTable("users",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "email",
+			Type: &schema.ColumnType{Type: &schema.StringType{T: "varchar", Size: 255}, Raw: "varchar(255)"},
+		},
+	).Tables[0]
+	tags := mockTable("tags",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+	).Tables[0]
+	pets := mockTable("pets",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "owner_id",
+			Type: &schema.ColumnType{Type: &schema.StringType{T: "varchar", Size: 255}, Raw: "varchar(255)", Null: true},
+		},
+		&schema.Column{
+			Name: "owner_email",
+			Type: &schema.ColumnType{Type: &schema.StringType{T: "varchar", Size: 255}, Raw: "varchar(255)", Null: true},
+		},
+		&schema.Column{
+			Name: "tag_id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "int"}, Raw: "int", Null: true},
+		},
+		&schema.Column{
+			Name: "friend_id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "int", Unsigned: true}, Raw: "int unsigned", Null: true},
+		},
+	).Tables[0]
+	pets.ForeignKeys = []*schema.ForeignKey{
+		{Symbol: "pets_owner_id", Table: pets, Columns: pets.Columns[1:2], RefTable: users, RefColumns: users.Columns[:1]},
+		{Symbol: "pets_owner_email", Table: pets, Columns: pets.Columns[2:3], RefTable: users, RefColumns: users.Columns[1:2]},
+		{Symbol: "pets_tag_id", Table: pets, Columns: pets.Columns[3:4], RefTable: tags, RefColumns: tags.Columns[:1]},
+		{Symbol: "pets_friend_id", Table: pets, Columns: pets.Columns[4:5], RefTable: users, RefColumns: users.Columns[:1]},
+	}
+	return &schema.Schema{
+		Name:   "test",
+		Tables: []*schema.Table{users, pets},
+	}
+}
+
+func MockPostgresRegexChecks() *schema.Schema {
+	s := mockTable("countries",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "integer"}, Raw: "integer"},
+		},
+		&schema.Column{
+			Name: "code",
+			Type: &schema.ColumnType{Type: &schema.StringType{T: "character varying", Size: 3}, Raw: "character varying"},
+		},
+		&schema.Column{
+			Name: "name",
+			Type: &schema.ColumnType{Type: &schema.StringType{T: "text"}, Raw: "text"},
+		},
+		&schema.Column{
+			Name: "domain",
+			Type: &schema.ColumnType{Type: &schema.StringType{T: "text"}, Raw: "text"},
+		},
+		&schema.Column{
+			Name: "population",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "integer"}, Raw: "integer"},
+		},
+	)
+	s.Tables[0].Attrs = []schema.Attr{
+		&schema.Check{Name: "countries_code_check", Expr: "((code)::text ~ '^[A-Z]{3}$'::text)"},
+		&schema.Check{Name: "countries_name_check", Expr: "(name ~* '^[a-z'' ]+$'::text)"},
+		&schema.Check{Name: "countries_domain_check", Expr: "(domain !~ '^www'::text)"},
+		&schema.Check{Name: "countries_population_check", Expr: "(population > 0)"},
+	}
+	return s
+}
+
+func MockMySQLBoolDefault() *schema.Schema {
+	return mockTable("users",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name:    "is_active",
+			Type:    &schema.ColumnType{Type: &schema.BoolType{T: "bool"}, Raw: "tinyint(1)"},
+			Default: &schema.Literal{V: "1"},
+		},
+		&schema.Column{
+			Name:    "is_admin",
+			Type:    &schema.ColumnType{Type: &schema.BoolType{T: "bool"}, Raw: "tinyint(1)", Null: true},
+			Default: &schema.Literal{V: "0"},
+		},
+		&schema.Column{
+			Name: "is_verified",
+			Type: &schema.ColumnType{Type: &schema.BoolType{T: "bool"}, Raw: "tinyint(1)"},
+		},
+	)
+}
+
+func MockMySQLPolymorphic() *schema.Schema {
+	return mockTable("comments",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "body",
+			Type: &schema.ColumnType{Type: &schema.StringType{T: "text"}, Raw: "text"},
+		},
+		&schema.Column{
+			Name: "commentable_type",
+			Type: &schema.ColumnType{Type: &schema.StringType{T: "varchar", Size: 255}, Raw: "varchar(255)"},
+		},
+		&schema.Column{
+			Name: "commentable_id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "content_type",
+			Type: &schema.ColumnType{Type: &schema.StringType{T: "varchar", Size: 255}, Raw: "varchar(255)"},
+		},
+	)
+}
+
+func MockPostgresMultipleSchemas() *schema.Realm {
+	column := func(name, typ string) *schema.Column {
+		return &schema.Column{
+			Name: name,
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: typ}, Raw: typ},
+		}
+	}
+	users := mockTable("users", column("id", "bigint")).Tables[0]
+	billingUsers := mockTable("users", column("id", "bigint")).Tables[0]
+	invoices := mockTable("invoices", column("id", "bigint"), column("user_id", "bigint")).Tables[0]
+	invoices.ForeignKeys = []*schema.ForeignKey{
+		{Symbol: "invoices_user_id_fkey", Table: invoices, Columns: invoices.Columns[1:], RefTable: users, RefColumns: users.Columns[:1]},
+	}
+	audits := mockTable("audits", column("id", "bigint")).Tables[0]
+	return schema.NewRealm(
+		schema.New("public").AddTables(users),
+		schema.New("billing").AddTables(billingUsers, invoices, audits),
+	)
+}
+
+func MockPostgresWeakEntity() *schema.Schema {
+	orders := mockTable("orders",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+	).Tables[0]
+	items := mockTable("order_items",
+		&schema.Column{
+			Name: "order_id",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}, Raw: "bigint"},
+		},
+		&schema.Column{
+			Name: "line",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "integer"}, Raw: "integer"},
+		},
+		&schema.Column{
+			Name: "quantity",
+			Type: &schema.ColumnType{Type: &schema.IntegerType{T: "integer"}, Raw: "integer"},
+		},
+	).Tables[0]
+	items.PrimaryKey.Parts = append(items.PrimaryKey.Parts, &schema.IndexPart{C: items.Columns[1]})
+	items.ForeignKeys = []*schema.ForeignKey{
+		{Symbol: "order_items_order_id_fkey", Table: items, Columns: items.Columns[:1], RefTable: orders, RefColumns: orders.Columns[:1]},
+	}
+	return &schema.Schema{
+		Name:   "public",
+		Tables: []*schema.Table{orders, items},
+	}
+}
+
 // Inspector is an autogenerated mock type for the Inspector type
 type inspectorMock struct {
 	mock.Mock
@@ -3984,8 +5721,14 @@ func (_m *inspectorMock) InspectTable(_ context.Context, _ string, _ *schema.Ins
 	return nil, nil
 }
 
-func (_m *inspectorMock) InspectRealm(_ context.Context, _ *schema.InspectRealmOption) (*schema.Realm, error) {
-	return nil, nil
+// InspectRealm provides a mock function with given fields: ctx, opts
+func (_m *inspectorMock) InspectRealm(ctx context.Context, opts *schema.InspectRealmOption) (*schema.Realm, error) {
+	ret := _m.Called(ctx, opts)
+	var r0 *schema.Realm
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*schema.Realm)
+	}
+	return r0, ret.Error(1)
 }
 
 // InspectSchema provides a mock function with given fields: ctx, name, opts
@@ -4062,3 +5805,754 @@ func mockMux(ctx context.Context, dlct string, data *schema.Schema, schemaName s
 	}, dlct)
 	return m
 }
+
+// importMutations runs the import on the given mock schema and returns its mutations.
+func importMutations(t *testing.T, dlct string, data *schema.Schema, opts ...entimport.ImportOption) []schemast.Mutator {
+	ctx := context.Background()
+	m := mockMux(ctx, dlct, data, "test")
+	drv, err := m.OpenImport(dlct + "://test")
+	require.NoError(t, err)
+	importer, err := entimport.NewImport(append(opts, entimport.WithDriver(drv))...)
+	require.NoError(t, err)
+	mutations, err := importer.SchemaMutations(ctx)
+	require.NoError(t, err)
+	return mutations
+}
+
+// importSchema runs the import on the given mock schema and returns the generated files by their names.
+func importSchema(t *testing.T, dlct string, data *schema.Schema, opts ...entimport.ImportOption) map[string]string {
+	mutations := importMutations(t, dlct, data, opts...)
+	schemas := createTempDir(t)
+	_, err := entimport.WriteSchema(mutations, append(opts, entimport.WithSchemaPath(schemas))...)
+	require.NoError(t, err)
+	return readDir(t, schemas)
+}
+
+// printMethod returns the source of the given method of a type declared in src.
+func printMethod(t *testing.T, src, typeName, methodName string) string {
+	f, err := parser.ParseFile(token.NewFileSet(), "", src, 0)
+	require.NoError(t, err)
+	m := lookupMethod(f, typeName, methodName)
+	require.NotNil(t, m)
+	var buf bytes.Buffer
+	require.NoError(t, printer.Fprint(&buf, token.NewFileSet(), m))
+	return buf.String()
+}
+
+// generateCode runs the ent code generation on the schema of the given mutations, and fails the test in case the
+// schema or the generated code does not compile. The schema is written to a package of this module for resolving
+// its imports, and is loaded by a generated program, as done by entc.Generate, which cannot load the packages
+// with the x/tools version of this module under recent Go toolchains.
+func generateCode(t *testing.T, mutations []schemast.Mutator) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	dir, err := ioutil.TempDir(wd, "entc")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, os.RemoveAll(dir))
+	})
+	pkg := "ariga.io/entimport/internal/entimport/" + filepath.Base(dir)
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "schema"), 0755))
+	_, err = entimport.WriteSchema(mutations, entimport.WithSchemaPath(filepath.Join(dir, "schema")))
+	require.NoError(t, err)
+	var names []string
+	for _, m := range mutations {
+		names = append(names, "schema."+m.(*schemast.UpsertSchema).Name+"{}")
+	}
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "internal"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "internal", "main.go"), []byte(`package main
+
+import (
+	"log"
+
+	"entgo.io/ent"
+	"entgo.io/ent/entc/gen"
+	"entgo.io/ent/entc/load"
+
+	"`+pkg+`/schema"
+)
+
+func main() {
+	var schemas []*load.Schema
+	for _, s := range []ent.Interface{`+strings.Join(names, ", ")+`} {
+		b, err := load.MarshalSchema(s)
+		if err != nil {
+			log.Fatal(err)
+		}
+		ls, err := load.UnmarshalSchema(b)
+		if err != nil {
+			log.Fatal(err)
+		}
+		schemas = append(schemas, ls)
+	}
+	storage, err := gen.NewStorage("sql")
+	if err != nil {
+		log.Fatal(err)
+	}
+	g, err := gen.NewGraph(&gen.Config{Target: "`+dir+`", Package: "`+pkg+`", Storage: storage}, schemas...)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := g.Gen(); err != nil {
+		log.Fatal(err)
+	}
+}
+`), 0644))
+	out, err := exec.Command("go", "run", "./"+filepath.Base(dir)+"/internal").CombinedOutput()
+	require.NoError(t, err, string(out))
+	out, err = exec.Command("go", "build", "./"+filepath.Base(dir)+"/...").CombinedOutput()
+	require.NoError(t, err, string(out))
+}
+
+func TestWithBaseSchema(t *testing.T) {
+	files := importSchema(t, dialect.MySQL, MockMySQLSingleTableFields(),
+		entimport.WithBaseSchema("github.com/org/project/ent/schema/base.Schema"),
+	)
+	require.Contains(t, files["user.go"], `"github.com/org/project/ent/schema/base"`)
+	require.Contains(t, files["user.go"], "type User struct {\n\tbase.Schema\n}")
+	require.NotContains(t, files["user.go"], "ent.Schema\n")
+}
+
+func TestWithTypeNamePrefix(t *testing.T) {
+	files := importSchema(t, dialect.MySQL, MockMySQLO2MTwoTypes(), entimport.WithTypeNamePrefix("DB"))
+	require.Len(t, files, 2)
+	require.Equal(t, `func (DBUser) Annotations() []schema.Annotation {
+	return []schema.Annotation{entsql.Annotation{Table: "users"}}
+}`, printMethod(t, files["d_b_user.go"], "DBUser", "Annotations"))
+	require.Equal(t, `func (DBUser) Edges() []ent.Edge {
+	return []ent.Edge{edge.To("pets", DBPet.Type)}
+}`, printMethod(t, files["d_b_user.go"], "DBUser", "Edges"))
+	require.Equal(t, `func (DBPet) Edges() []ent.Edge {
+	return []ent.Edge{edge.From("user", DBUser.Type).Ref("pets").Unique().Field("user_pets")}
+}`, printMethod(t, files["d_b_pet.go"], "DBPet", "Edges"))
+}
+
+func TestWithIncludedColumns(t *testing.T) {
+	files := importSchema(t, dialect.MySQL, MockMySQLO2MTwoTypes(), entimport.WithIncludedColumns(map[string][]string{
+		"users": {"name"},
+		"pets":  {"name"},
+	}))
+	require.Equal(t, `func (User) Fields() []ent.Field {
+	return []ent.Field{field.Int("id"), field.String("name")}
+}`, printMethod(t, files["user.go"], "User", "Fields"))
+	require.Equal(t, `func (Pet) Fields() []ent.Field {
+	return []ent.Field{field.Int("id"), field.String("name")}
+}`, printMethod(t, files["pet.go"], "Pet", "Fields"))
+	for name, typ := range map[string]string{"user.go": "User", "pet.go": "Pet"} {
+		require.Equal(t, "func ("+typ+") Edges() []ent.Edge {\n\treturn nil\n}", printMethod(t, files[name], typ, "Edges"))
+	}
+}
+
+func TestWithExcludedColumns(t *testing.T) {
+	files := importSchema(t, dialect.MySQL, MockMySQLO2MTwoTypes(), entimport.WithExcludedColumns(map[string][]string{
+		"users": {"age", "missing"},
+		"pets":  {"name"},
+	}))
+	require.Equal(t, `func (User) Fields() []ent.Field {
+	return []ent.Field{field.Int("id"), field.String("name")}
+}`, printMethod(t, files["user.go"], "User", "Fields"))
+	require.Equal(t, `func (Pet) Fields() []ent.Field {
+	return []ent.Field{field.Int("id"), field.Int("user_pets").Optional()}
+}`, printMethod(t, files["pet.go"], "Pet", "Fields"))
+	require.Equal(t, `func (User) Edges() []ent.Edge {
+	return []ent.Edge{edge.To("pets", Pet.Type)}
+}`, printMethod(t, files["user.go"], "User", "Edges"))
+
+	ctx := context.Background()
+	m := mockMux(ctx, dialect.MySQL, MockMySQLO2MTwoTypes(), "test")
+	drv, err := m.OpenImport("mysql://test")
+	require.NoError(t, err)
+	for columns, msg := range map[string]string{
+		"user_pets": `entimport: issue with table pets: column "user_pets" cannot be excluded, as it is part of the foreign key "pets_users_pets" referencing table "users"`,
+		"id":        `entimport: issue with table pets: column "id" cannot be excluded, as it is part of the primary key`,
+	} {
+		importer, err := entimport.NewImport(entimport.WithDriver(drv), entimport.WithExcludedColumns(map[string][]string{"pets": {columns}}))
+		require.NoError(t, err)
+		_, err = importer.SchemaMutations(ctx)
+		require.EqualError(t, err, msg)
+	}
+}
+
+func TestWithIDPosition(t *testing.T) {
+	files := importSchema(t, dialect.MySQL, MockMySQLO2MTwoTypes(), entimport.WithIDPosition(entimport.Last))
+	require.Equal(t, `func (User) Fields() []ent.Field {
+	return []ent.Field{field.Int("age"), field.String("name"), field.Int("id")}
+}`, printMethod(t, files["user.go"], "User", "Fields"))
+	require.Equal(t, `func (Pet) Fields() []ent.Field {
+	return []ent.Field{field.String("name"), field.Int("user_pets").Optional(), field.Int("id")}
+}`, printMethod(t, files["pet.go"], "Pet", "Fields"))
+	files = importSchema(t, dialect.MySQL, MockMySQLO2MTwoTypes(), entimport.WithIDPosition(entimport.First))
+	require.Equal(t, `func (User) Fields() []ent.Field {
+	return []ent.Field{field.Int("id"), field.Int("age"), field.String("name")}
+}`, printMethod(t, files["user.go"], "User", "Fields"))
+}
+
+func TestWithIntegerPolicy(t *testing.T) {
+	files := importSchema(t, dialect.MySQL, MockMySQLO2MSignednessMismatch(), entimport.WithIntegerPolicy(entimport.Signed))
+	require.Equal(t, `func (User) Fields() []ent.Field {
+	return []ent.Field{field.Int("id").SchemaType(map[string]string{"mysql": "bigint unsigned"}), field.String("name")}
+}`, printMethod(t, files["user.go"], "User", "Fields"))
+	files = importSchema(t, dialect.Postgres, MockPostgresSingleTableFields(), entimport.WithIntegerPolicy(entimport.Unsigned))
+	require.Equal(t, `func (User) Fields() []ent.Field {
+	return []ent.Field{field.Uint64("id").SchemaType(map[string]string{"postgres": "bigint"}), field.Uint16("age").SchemaType(map[string]string{"postgres": "smallint"}), field.String("name")}
+}`, printMethod(t, files["user.go"], "User", "Fields"))
+}
+
+func TestWithoutAnnotations(t *testing.T) {
+	files := importSchema(t, dialect.MySQL, MockMySQLO2MTwoTypes(), entimport.WithTypeNamePrefix("DB"), entimport.WithoutAnnotations(true))
+	for name, src := range files {
+		f, err := parser.ParseFile(token.NewFileSet(), name, src, 0)
+		require.NoError(t, err)
+		require.Nil(t, lookupMethod(f, "DBUser", "Annotations"))
+		require.Nil(t, lookupMethod(f, "DBPet", "Annotations"))
+		require.NotContains(t, src, `"entgo.io/ent/dialect/entsql"`)
+		require.NotContains(t, src, `"entgo.io/ent/schema"`)
+	}
+	require.Equal(t, `func (DBUser) Edges() []ent.Edge {
+	return []ent.Edge{edge.To("pets", DBPet.Type)}
+}`, printMethod(t, files["d_b_user.go"], "DBUser", "Edges"))
+}
+
+func TestWithEnumColumns(t *testing.T) {
+	files := importSchema(t, dialect.MySQL, MockMySQLIntEnums(), entimport.WithEnumColumns(map[string][]string{
+		"tickets.priority": {"low", "high"},
+		"tickets.state":    {"open", "closed"},
+		"tickets.score":    {"a", "b"},
+	}))
+	require.Equal(t, `func (Ticket) Fields() []ent.Field {
+	return []ent.Field{field.Int("id"), field.Enum("priority").Optional().SchemaType(map[string]string{"mysql": "tinyint(4)"}).Values("low", "high"), field.Enum("state").Values("open", "closed"), field.Float("score")}
+}`, printMethod(t, files["ticket.go"], "Ticket", "Fields"))
+	files = importSchema(t, dialect.MySQL, MockMySQLIntEnums(), entimport.WithEnumColumns(map[string][]string{
+		"tickets.priority": {"low", "high"},
+	}), entimport.WithEnumGoType(map[string]string{
+		"tickets.priority": "github.com/org/project/types.Priority",
+	}))
+	require.Contains(t, printMethod(t, files["ticket.go"], "Ticket", "Fields"), `field.Enum("priority").Optional().SchemaType(map[string]string{"mysql": "tinyint(4)"}).Values().GoType(types.Priority(""))`)
+}
+
+func TestWithEnumGoType(t *testing.T) {
+	files := importSchema(t, dialect.MySQL, MockMySQLEnumFields())
+	require.Equal(t, `func (Order) Fields() []ent.Field {
+	return []ent.Field{field.Int("id"), field.Enum("status").Values("pending", "shipped"), field.Enum("kind").Values("online", "store")}
+}`, printMethod(t, files["order.go"], "Order", "Fields"))
+	files = importSchema(t, dialect.MySQL, MockMySQLEnumFields(), entimport.WithEnumGoType(map[string]string{
+		"orders.status": "github.com/org/project/types.Status",
+	}))
+	require.Contains(t, files["order.go"], `"github.com/org/project/types"`)
+	require.Equal(t, `func (Order) Fields() []ent.Field {
+	return []ent.Field{field.Int("id"), field.Enum("status").Values().GoType(types.Status("")), field.Enum("kind").Values("online", "store")}
+}`, printMethod(t, files["order.go"], "Order", "Fields"))
+}
+
+func TestWithBytesGoType(t *testing.T) {
+	files := importSchema(t, dialect.Postgres, MockPostgresBytesDefault(), entimport.WithBytesGoType(map[string]string{
+		"files.digest": "github.com/org/project/crypto.Blob",
+		"files.id":     "github.com/org/project/crypto.Blob",
+	}))
+	require.Contains(t, files["file.go"], `"github.com/org/project/crypto"`)
+	require.Equal(t, `func (File) Fields() []ent.Field {
+	return []ent.Field{field.Int("id"), field.Bytes("header").Default([]byte{0x0, 0xff}), field.Bytes("magic").Default([]byte{0x61, 0x62, 0x1, 0x5c}), field.Bytes("digest").GoType(crypto.Blob{})}
+}`, printMethod(t, files["file.go"], "File", "Fields"))
+}
+
+func TestWithFileNaming(t *testing.T) {
+	files := importSchema(t, dialect.MySQL, MockMySQLO2MTwoTypes(), entimport.WithFileNaming(func(typeName string) string {
+		return typeName + "_schema.go"
+	}))
+	require.Len(t, files, 2)
+	require.Contains(t, files, "User_schema.go")
+	require.Contains(t, files, "Pet_schema.go")
+	require.Equal(t, `func (User) Edges() []ent.Edge {
+	return []ent.Edge{edge.To("pets", Pet.Type)}
+}`, printMethod(t, files["User_schema.go"], "User", "Edges"))
+}
+
+func TestWithFieldGrouping(t *testing.T) {
+	files := importSchema(t, dialect.MySQL, MockMySQLMixedColumns())
+	require.Equal(t, `func (Post) Fields() []ent.Field {
+	return []ent.Field{field.Int("id"), field.Int("author_id").Optional(), field.String("title"), field.Int("editor_id").Optional(), field.Text("body").SchemaType(map[string]string{"mysql": "text"})}
+}`, printMethod(t, files["post.go"], "Post", "Fields"))
+	files = importSchema(t, dialect.MySQL, MockMySQLMixedColumns(), entimport.WithFieldGrouping(true))
+	require.Equal(t, `func (Post) Fields() []ent.Field {
+	return []ent.Field{field.Int("id"), field.String("title"), field.Text("body").SchemaType(map[string]string{"mysql": "text"}), field.Int("author_id").Optional(), field.Int("editor_id").Optional()}
+}`, printMethod(t, files["post.go"], "Post", "Fields"))
+}
+
+func TestWithEntImportPath(t *testing.T) {
+	files := importSchema(t, dialect.MySQL, MockMySQLO2MTwoTypes(), entimport.WithEntImportPath("github.com/org/ent"))
+	for _, src := range files {
+		require.NotContains(t, src, `"entgo.io/ent`)
+		require.Contains(t, src, `"github.com/org/ent"`)
+		require.Contains(t, src, `"github.com/org/ent/schema/edge"`)
+		require.Contains(t, src, `"github.com/org/ent/schema/field"`)
+	}
+	require.Equal(t, `func (User) Edges() []ent.Edge {
+	return []ent.Edge{edge.To("pets", Pet.Type)}
+}`, printMethod(t, files["user.go"], "User", "Edges"))
+}
+
+func TestNoTables(t *testing.T) {
+	ctx := context.Background()
+	for _, tt := range []struct {
+		dialect string
+		mock    *schema.Schema
+		opts    []entimport.ImportOption
+	}{
+		{dialect: dialect.MySQL, mock: &schema.Schema{Name: "test"}},
+		{dialect: dialect.Postgres, mock: &schema.Schema{Name: "test"}},
+		{dialect: dialect.MySQL, mock: MockMySQLSingleTableFields(), opts: []entimport.ImportOption{entimport.WithExcludedTables([]string{"users"})}},
+	} {
+		m := mockMux(ctx, tt.dialect, tt.mock, "test")
+		drv, err := m.OpenImport(tt.dialect + "://test")
+		require.NoError(t, err)
+		importer, err := entimport.NewImport(append(tt.opts, entimport.WithDriver(drv))...)
+		require.NoError(t, err)
+		mutations, err := importer.SchemaMutations(ctx)
+		require.Empty(t, mutations)
+		require.ErrorIs(t, err, entimport.ErrNoTables)
+		require.EqualError(t, err, "entimport: no tables found to import")
+	}
+}
+
+func TestWithSafeIdentifiers(t *testing.T) {
+	files := importSchema(t, dialect.MySQL, MockMySQLKeywordColumns())
+	require.Equal(t, `func (Pet) Fields() []ent.Field {
+	return []ent.Field{field.Int("id"), field.String("type"), field.String("name"), field.Int("go").Optional()}
+}`, printMethod(t, files["pet.go"], "Pet", "Fields"))
+	files = importSchema(t, dialect.MySQL, MockMySQLKeywordColumns(), entimport.WithSafeIdentifiers(true))
+	require.Equal(t, `func (Pet) Fields() []ent.Field {
+	return []ent.Field{field.Int("id"), field.String("type_field").StorageKey("type"), field.String("name"), field.Int("go_field").Optional().StorageKey("go")}
+}`, printMethod(t, files["pet.go"], "Pet", "Fields"))
+	require.Equal(t, `func (Pet) Edges() []ent.Edge {
+	return []ent.Edge{edge.From("user", User.Type).Ref("pets").Unique().Field("go_field")}
+}`, printMethod(t, files["pet.go"], "Pet", "Edges"))
+}
+
+func TestWithForeignKeyValidation(t *testing.T) {
+	ctx := context.Background()
+	m := mockMux(ctx, dialect.MySQL, MockMySQLForeignKeyMismatch(), "test")
+	drv, err := m.OpenImport("mysql://test")
+	require.NoError(t, err)
+	importer, err := entimport.NewImport(entimport.WithDriver(drv))
+	require.NoError(t, err)
+	_, err = importer.SchemaMutations(ctx)
+	require.NoError(t, err)
+
+	importer, err = entimport.NewImport(entimport.WithDriver(drv), entimport.WithForeignKeyValidation(true))
+	require.NoError(t, err)
+	mutations, err := importer.SchemaMutations(ctx)
+	require.Nil(t, mutations)
+	require.EqualError(t, err, `entimport: foreign key "pets_owner_id" of column pets.owner_id of type string references the primary key of table "users" of type int
+entimport: foreign key "pets_owner_email" of column pets.owner_email references column "email" of table "users" that is not its primary key
+entimport: foreign key "pets_tag_id" of column pets.tag_id references table "tags" that is not imported`)
+
+	importer, err = entimport.NewImport(entimport.WithDriver(drv), entimport.WithForeignKeyValidation(true), entimport.WithStubMissingRefs(true),
+		entimport.WithIncludedColumns(map[string][]string{"pets": {"tag_id", "friend_id"}}))
+	require.NoError(t, err)
+	_, err = importer.SchemaMutations(ctx)
+	require.NoError(t, err)
+
+	// The referenced primary key is resolved by its field name, in case its column is not named "id".
+	mock := MockMySQLForeignKeyMismatch()
+	mock.Tables[0].Columns[0].Name = "user_id"
+	drv, err = mockMux(ctx, dialect.MySQL, mock, "test").OpenImport("mysql://test")
+	require.NoError(t, err)
+	importer, err = entimport.NewImport(entimport.WithDriver(drv), entimport.WithForeignKeyValidation(true), entimport.WithStubMissingRefs(true),
+		entimport.WithIncludedColumns(map[string][]string{"pets": {"owner_id"}}))
+	require.NoError(t, err)
+	_, err = importer.SchemaMutations(ctx)
+	require.EqualError(t, err, `entimport: foreign key "pets_owner_id" of column pets.owner_id of type string references the primary key of table "users" of type int`)
+	// A non-key column named "id" is not the referenced primary key.
+	mock = MockMySQLO2MSignednessMismatch()
+	mock.Tables[0].Columns[0].Name = "uid"
+	mock.Tables[0].Columns = append(mock.Tables[0].Columns, &schema.Column{
+		Name: "id",
+		Type: &schema.ColumnType{Type: &schema.StringType{T: "varchar", Size: 255}, Raw: "varchar(255)"},
+	})
+	drv, err = mockMux(ctx, dialect.MySQL, mock, "test").OpenImport("mysql://test")
+	require.NoError(t, err)
+	importer, err = entimport.NewImport(entimport.WithDriver(drv), entimport.WithForeignKeyValidation(true))
+	require.NoError(t, err)
+	_, err = importer.SchemaMutations(ctx)
+	require.NoError(t, err)
+}
+
+func TestWithCollectErrors(t *testing.T) {
+	ctx := context.Background()
+	m := mockMux(ctx, dialect.MySQL, MockMySQLUnsupportedColumns(), "test")
+	drv, err := m.OpenImport("mysql://test")
+	require.NoError(t, err)
+	importer, err := entimport.NewImport(entimport.WithDriver(drv))
+	require.NoError(t, err)
+	_, err = importer.SchemaMutations(ctx)
+	require.ErrorContains(t, err, "issue with table places")
+	require.NotContains(t, err.Error(), "areas")
+
+	importer, err = entimport.NewImport(entimport.WithDriver(drv), entimport.WithCollectErrors(true))
+	require.NoError(t, err)
+	mutations, err := importer.SchemaMutations(ctx)
+	require.Nil(t, mutations)
+	require.ErrorContains(t, err, "issue with table places")
+	require.ErrorContains(t, err, "issue with table areas")
+}
+
+func TestWithTableAnnotations(t *testing.T) {
+	files := importSchema(t, dialect.MySQL, MockMySQLO2MTwoTypes(), entimport.WithTableAnnotations(func(table string) []entschema.Annotation {
+		if table == "users" {
+			return []entschema.Annotation{entsql.Annotation{Charset: "utf8mb4"}}
+		}
+		return []entschema.Annotation{entsql.Annotation{Collation: "utf8mb4_bin"}}
+	}))
+	require.Equal(t, `func (User) Annotations() []schema.Annotation {
+	return []schema.Annotation{entsql.Annotation{Charset: "utf8mb4"}}
+}`, printMethod(t, files["user.go"], "User", "Annotations"))
+	require.Equal(t, `func (Pet) Annotations() []schema.Annotation {
+	return []schema.Annotation{entsql.Annotation{Collation: "utf8mb4_bin"}}
+}`, printMethod(t, files["pet.go"], "Pet", "Annotations"))
+}
+
+func TestWithImmutableColumns(t *testing.T) {
+	files := importSchema(t, dialect.MySQL, MockMySQLTimestamps())
+	require.Equal(t, `func (Post) Fields() []ent.Field {
+	return []ent.Field{field.Int("id"), field.Time("created_at"), field.Time("updated_at")}
+}`, printMethod(t, files["post.go"], "Post", "Fields"))
+	files = importSchema(t, dialect.MySQL, MockMySQLTimestamps(), entimport.WithImmutableColumns([]string{"id", "created_at"}))
+	require.Equal(t, `func (Post) Fields() []ent.Field {
+	return []ent.Field{field.Int("id").Immutable(), field.Time("created_at").Immutable(), field.Time("updated_at")}
+}`, printMethod(t, files["post.go"], "Post", "Fields"))
+	// Foreign key columns back edges, and are not marked.
+	mutations := importMutations(t, dialect.MySQL, MockMySQLO2MTwoTypes(), entimport.WithImmutableColumns([]string{"user_pets", "name"}))
+	files = importSchema(t, dialect.MySQL, MockMySQLO2MTwoTypes(), entimport.WithImmutableColumns([]string{"user_pets", "name"}))
+	require.Equal(t, `func (Pet) Fields() []ent.Field {
+	return []ent.Field{field.Int("id"), field.String("name").Immutable(), field.Int("user_pets").Optional()}
+}`, printMethod(t, files["pet.go"], "Pet", "Fields"))
+	generateCode(t, mutations)
+}
+
+func TestWriteSchemaFiles(t *testing.T) {
+	schemas := createTempDir(t)
+	// Files that do not hold the imported types are not reported.
+	require.NoError(t, os.WriteFile(filepath.Join(schemas, "mixin.go"), []byte("package schema\n\ntype Mixin struct{}\n"), 0600))
+	files, err := entimport.WriteSchema(importMutations(t, dialect.MySQL, MockMySQLO2MTwoTypes()), entimport.WithSchemaPath(schemas),
+		entimport.WithFileNaming(func(typeName string) string {
+			return typeName + "_schema.go"
+		}))
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(schemas, "Pet_schema.go"), filepath.Join(schemas, "User_schema.go")}, files)
+}
+
+func TestWriteSchemaFileMode(t *testing.T) {
+	schemas := createTempDir(t)
+	_, err := entimport.WriteSchema(importMutations(t, dialect.MySQL, MockMySQLSingleTableFields()), entimport.WithSchemaPath(schemas))
+	require.NoError(t, err)
+	user := filepath.Join(schemas, "user.go")
+	info, err := os.Stat(user)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0644), info.Mode().Perm())
+	// Existing files keep their permissions.
+	require.NoError(t, os.Chmod(user, 0640))
+	_, err = entimport.WriteSchema(importMutations(t, dialect.MySQL, MockMySQLO2MTwoTypes()), entimport.WithSchemaPath(schemas))
+	require.NoError(t, err)
+	info, err = os.Stat(user)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0640), info.Mode().Perm())
+	info, err = os.Stat(filepath.Join(schemas, "pet.go"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0644), info.Mode().Perm())
+}
+
+func TestWithPackageName(t *testing.T) {
+	mutations := importMutations(t, dialect.MySQL, MockMySQLO2MTwoTypes())
+	schemas := createTempDir(t)
+	const banner = "Code generated by entimport, DO NOT EDIT.\nSource: [schema.sql](https://example.com/schema.sql?v=1)"
+	// Writing the schema again does not repeat the header.
+	for n := 0; n < 2; n++ {
+		_, err := entimport.WriteSchema(mutations, entimport.WithSchemaPath(schemas), entimport.WithPackageName("entschema"), entimport.WithHeader(banner))
+		require.NoError(t, err)
+	}
+	files := readDir(t, schemas)
+	require.Len(t, files, 2)
+	for _, f := range files {
+		require.True(t, strings.HasPrefix(f, "// Code generated by entimport, DO NOT EDIT.\n// Source: [schema.sql](https://example.com/schema.sql?v=1)\n\npackage entschema\n"), f)
+		require.Equal(t, 1, strings.Count(f, "// Source:"))
+	}
+}
+
+func TestWithGenerateFile(t *testing.T) {
+	mutations := importMutations(t, dialect.MySQL, MockMySQLO2MTwoTypes())
+	schemas := filepath.Join(createTempDir(t), "ent", "schema")
+	require.NoError(t, os.MkdirAll(schemas, 0755))
+	_, err := entimport.WriteSchema(mutations, entimport.WithSchemaPath(schemas))
+	require.NoError(t, err)
+	fn := filepath.Join(filepath.Dir(schemas), "generate.go")
+	require.NoFileExists(t, fn)
+	_, err = entimport.WriteSchema(mutations, entimport.WithSchemaPath(schemas), entimport.WithGenerateFile(true))
+	require.NoError(t, err)
+	buf, err := os.ReadFile(fn)
+	require.NoError(t, err)
+	require.Equal(t, "package ent\n\n//go:generate go run entgo.io/ent/cmd/ent generate ./schema\n", string(buf))
+	// Existing files are kept.
+	require.NoError(t, os.WriteFile(fn, []byte("package ent\n"), 0600))
+	_, err = entimport.WriteSchema(mutations, entimport.WithSchemaPath(schemas), entimport.WithGenerateFile(true))
+	require.NoError(t, err)
+	buf, err = os.ReadFile(fn)
+	require.NoError(t, err)
+	require.Equal(t, "package ent\n", string(buf))
+}
+
+func TestRegisterFieldMapper(t *testing.T) {
+	const dlct = "fake"
+	ctx := context.Background()
+	m := mockMux(ctx, dlct, mockTable("events",
+		&schema.Column{
+			Name: "id",
+			Type: &schema.ColumnType{Type: &schema.UnsupportedType{T: "UInt64"}, Raw: "UInt64"},
+		},
+		&schema.Column{
+			Name:  "name",
+			Type:  &schema.ColumnType{Type: &schema.UnsupportedType{T: "String"}, Raw: "String", Null: true},
+			Attrs: []schema.Attr{&schema.Comment{Text: "event name"}},
+		},
+	), "test")
+	drv, err := m.OpenImportDialect(dlct, "test")
+	require.NoError(t, err)
+	_, err = entimport.NewImport(entimport.WithDriver(drv))
+	require.EqualError(t, err, `entimport: unsupported dialect "fake", register a field mapper for it with RegisterFieldMapper`)
+
+	entimport.RegisterFieldMapper(dlct, func(column *schema.Column) (ent.Field, error) {
+		switch column.Type.Raw {
+		case "UInt64":
+			return field.Uint64(column.Name), nil
+		case "String":
+			return field.String(column.Name), nil
+		}
+		return nil, nil
+	})
+	importer, err := entimport.NewImport(entimport.WithDriver(drv))
+	require.NoError(t, err)
+	mutations, err := importer.SchemaMutations(ctx)
+	require.NoError(t, err)
+	schemas := createTempDir(t)
+	_, err = entimport.WriteSchema(mutations, entimport.WithSchemaPath(schemas))
+	require.NoError(t, err)
+	files := readDir(t, schemas)
+	require.Equal(t, `func (Event) Fields() []ent.Field {
+	return []ent.Field{field.Uint64("id"), field.String("name").Optional().Comment("event name")}
+}`, printMethod(t, files["event.go"], "Event", "Fields"))
+}
+
+func TestWithPostCommand(t *testing.T) {
+	mutations := importMutations(t, dialect.MySQL, MockMySQLSingleTableFields())
+	schemas := createTempDir(t)
+	_, err := entimport.WriteSchema(mutations, entimport.WithSchemaPath(schemas),
+		entimport.WithPostCommand([]string{"sh", "-c", `ls "$0" > "$0/post.txt"`}))
+	require.NoError(t, err)
+	out, err := os.ReadFile(filepath.Join(schemas, "post.txt"))
+	require.NoError(t, err)
+	require.Contains(t, string(out), "user.go")
+	_, err = entimport.WriteSchema(mutations, entimport.WithSchemaPath(schemas),
+		entimport.WithPostCommand([]string{"sh", "-c", "echo invalid syntax; exit 1"}))
+	require.ErrorContains(t, err, "invalid syntax")
+}
+
+func TestWithFieldNameMap(t *testing.T) {
+	files := importSchema(t, dialect.MySQL, MockMySQLO2MTwoTypes(), entimport.WithFieldNameMap(map[string]string{
+		"users.name":     "username",
+		"pets.user_pets": "owner_id",
+	}))
+	require.Equal(t, `func (User) Fields() []ent.Field {
+	return []ent.Field{field.Int("id"), field.Int("age"), field.String("username").StorageKey("name")}
+}`, printMethod(t, files["user.go"], "User", "Fields"))
+	require.Equal(t, `func (Pet) Fields() []ent.Field {
+	return []ent.Field{field.Int("id"), field.String("name"), field.Int("owner_id").Optional().StorageKey("user_pets")}
+}`, printMethod(t, files["pet.go"], "Pet", "Fields"))
+	require.Equal(t, `func (Pet) Edges() []ent.Edge {
+	return []ent.Edge{edge.From("user", User.Type).Ref("pets").Unique().Field("owner_id")}
+}`, printMethod(t, files["pet.go"], "Pet", "Edges"))
+}
+
+func TestWithEdgeComments(t *testing.T) {
+	files := importSchema(t, dialect.MySQL, MockMySQLO2MTwoTypes())
+	require.NotContains(t, files["pet.go"], "derived from")
+	files = importSchema(t, dialect.MySQL, MockMySQLO2MTwoTypes(), entimport.WithEdgeComments(true))
+	require.Contains(t, files["user.go"], "// pets: derived from FK pets_users_pets (pets.user_pets)\nfunc (User) Edges() []ent.Edge {")
+	require.Contains(t, files["pet.go"], "// user: derived from FK pets_users_pets (pets.user_pets)\nfunc (Pet) Edges() []ent.Edge {")
+	files = importSchema(t, dialect.MySQL, MockMySQLM2MTwoTypes(), entimport.WithEdgeComments(true))
+	require.Contains(t, files["group.go"], "// users: derived from join table group_users\nfunc (Group) Edges() []ent.Edge {")
+	require.Contains(t, files["user.go"], "// groups: derived from join table group_users\nfunc (User) Edges() []ent.Edge {")
+}
+
+func TestWithInt64Columns(t *testing.T) {
+	files := importSchema(t, dialect.MySQL, MockMySQLCounters(), entimport.WithInt64Columns([]string{"views"}))
+	require.Equal(t, `func (Stat) Fields() []ent.Field {
+	return []ent.Field{field.Int("id"), field.Int64("views"), field.Int("likes")}
+}`, printMethod(t, files["stat.go"], "Stat", "Fields"))
+	files = importSchema(t, dialect.MySQL, MockMySQLCounters(), entimport.WithInt64Columns([]string{"views"}), entimport.WithIntegerPolicy(entimport.Unsigned))
+	require.Equal(t, `func (Stat) Fields() []ent.Field {
+	return []ent.Field{field.Uint64("id").SchemaType(map[string]string{"mysql": "bigint"}), field.Uint64("views").SchemaType(map[string]string{"mysql": "bigint"}), field.Uint64("likes").SchemaType(map[string]string{"mysql": "bigint"})}
+}`, printMethod(t, files["stat.go"], "Stat", "Fields"))
+}
+
+func TestWithPKStorageKey(t *testing.T) {
+	files := importSchema(t, dialect.MySQL, MockMySQLNonDefaultPrimaryKey())
+	require.Contains(t, printMethod(t, files["user.go"], "User", "Fields"), `field.String("id").StorageKey("name")`)
+	files = importSchema(t, dialect.MySQL, MockMySQLNonDefaultPrimaryKey(), entimport.WithPKStorageKey(false))
+	require.Equal(t, `func (User) Fields() []ent.Field {
+	return []ent.Field{field.String("id"), field.String("last_name").Unique()}
+}`, printMethod(t, files["user.go"], "User", "Fields"))
+}
+
+func TestWithInferredEdges(t *testing.T) {
+	files := importSchema(t, dialect.MySQL, MockMySQLLookupTableWithoutFK())
+	require.Contains(t, files["order.go"], "func (Order) Edges() []ent.Edge {\n\treturn nil\n}")
+	files = importSchema(t, dialect.MySQL, MockMySQLLookupTableWithoutFK(), entimport.WithInferredEdges("{table}_id"), entimport.WithEdgeComments(true))
+	require.Equal(t, `func (Order) Fields() []ent.Field {
+	return []ent.Field{field.Int("id"), field.Int("category_id").Optional(), field.String("tracking_id")}
+}`, printMethod(t, files["order.go"], "Order", "Fields"))
+	require.Contains(t, files["order.go"], `// category: derived from column orders.category_id (inferred)
+func (Order) Edges() []ent.Edge {
+	return []ent.Edge{edge.From("category", Category.Type).Ref("orders").Unique().Field("category_id")}
+}`)
+	require.Equal(t, `func (Category) Edges() []ent.Edge {
+	return []ent.Edge{edge.To("orders", Order.Type)}
+}`, printMethod(t, files["category.go"], "Category", "Edges"))
+	ctx := context.Background()
+	drv, err := mockMux(ctx, dialect.MySQL, MockMySQLLookupTableWithoutFK(), "test").OpenImport("mysql://test")
+	require.NoError(t, err)
+	importer, err := entimport.NewImport(entimport.WithDriver(drv), entimport.WithInferredEdges("category"))
+	require.NoError(t, err)
+	_, err = importer.SchemaMutations(ctx)
+	require.ErrorContains(t, err, `invalid edge inference pattern "category"`)
+}
+
+func TestWithMaxTables(t *testing.T) {
+	ctx := context.Background()
+	drv, err := mockMux(ctx, dialect.MySQL, MockMySQLO2MTwoTypes(), "test").OpenImport("mysql://test")
+	require.NoError(t, err)
+	importer, err := entimport.NewImport(entimport.WithDriver(drv), entimport.WithMaxTables(1))
+	require.NoError(t, err)
+	_, err = importer.SchemaMutations(ctx)
+	require.EqualError(t, err, "entimport: found 2 tables to import, exceeding the limit of 1 tables; narrow the selection of tables to import")
+	importer, err = entimport.NewImport(entimport.WithDriver(drv), entimport.WithMaxTables(1), entimport.WithExcludedTables([]string{"pets"}))
+	require.NoError(t, err)
+	mutations, err := importer.SchemaMutations(ctx)
+	require.NoError(t, err)
+	require.Len(t, mutations, 1)
+}
+
+func TestO2OBidirectionalOwnership(t *testing.T) {
+	for dlct, mock := range map[string]*schema.Schema{
+		dialect.MySQL:    MockMySQLO2OBidirectional(),
+		dialect.Postgres: MockPostgresO2OBidirectional(),
+	} {
+		t.Run(dlct, func(t *testing.T) {
+			mutations := importMutations(t, dlct, mock)
+			require.Len(t, mutations, 1)
+			node := mutations[0].(*schemast.UpsertSchema)
+			require.Len(t, node.Edges, 2)
+			// The inverse (From) edge owns the foreign key column, and both edges are unique.
+			to, from := node.Edges[0].Descriptor(), node.Edges[1].Descriptor()
+			require.False(t, to.Inverse)
+			require.Empty(t, to.Field)
+			require.True(t, to.Unique)
+			require.True(t, from.Inverse)
+			require.Equal(t, "user_spouse", from.Field)
+			require.Equal(t, to.Name, from.RefName)
+			require.True(t, from.Unique)
+			// The loaded schema passes the validation of the ent code generator.
+			s := &load.Schema{Name: node.Name}
+			for _, f := range node.Fields {
+				lf, err := load.NewField(f.Descriptor())
+				require.NoError(t, err)
+				s.Fields = append(s.Fields, lf)
+			}
+			for _, e := range node.Edges {
+				s.Edges = append(s.Edges, load.NewEdge(e.Descriptor()))
+			}
+			storage, err := gen.NewStorage("sql")
+			require.NoError(t, err)
+			g, err := gen.NewGraph(&gen.Config{Package: "entimport/ent", Storage: storage}, s)
+			require.NoError(t, err)
+			owner := g.Nodes[0].Edges[1]
+			require.True(t, owner.IsInverse())
+			require.True(t, owner.OwnFK())
+			require.Equal(t, "user_spouse", owner.Field().Name)
+			require.True(t, owner.Unique && owner.Ref.Unique)
+		})
+	}
+}
+
+// listInflector names non-unique edges with a "_list" suffix.
+type listInflector struct{}
+
+func (listInflector) Plural(typeName string) string { return strings.ToLower(typeName) + "_list" }
+
+func (listInflector) Singular(edgeName string) string { return strings.TrimSuffix(edgeName, "_list") }
+
+func TestWithEdgeInflector(t *testing.T) {
+	files := importSchema(t, dialect.MySQL, MockMySQLO2MTwoTypes(), entimport.WithEdgeInflector(listInflector{}))
+	require.Len(t, files, 2)
+	require.Contains(t, files, "user.go")
+	require.Contains(t, files, "pet.go")
+	require.Equal(t, `func (User) Edges() []ent.Edge {
+	return []ent.Edge{edge.To("pet_list", Pet.Type)}
+}`, printMethod(t, files["user.go"], "User", "Edges"))
+	require.Equal(t, `func (Pet) Edges() []ent.Edge {
+	return []ent.Edge{edge.From("user", User.Type).Ref("pet_list").Unique().Field("user_pets")}
+}`, printMethod(t, files["pet.go"], "Pet", "Edges"))
+}
+
+func TestWithAtomicWrite(t *testing.T) {
+	parent := createTempDir(t)
+	schemas := filepath.Join(parent, "schema")
+	_, err := entimport.WriteSchema(importMutations(t, dialect.MySQL, MockMySQLSingleTableFields()),
+		entimport.WithSchemaPath(schemas), entimport.WithAtomicWrite(true))
+	require.NoError(t, err)
+	original := readDir(t, schemas)
+	require.Contains(t, original, "user.go")
+	info, err := os.Stat(schemas)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0755), info.Mode().Perm())
+	// A failure after the files were written leaves the schema directory untouched.
+	mutations := importMutations(t, dialect.MySQL, MockMySQLO2MTwoTypes())
+	_, err = entimport.WriteSchema(mutations, entimport.WithSchemaPath(schemas), entimport.WithAtomicWrite(true),
+		entimport.WithPostCommand([]string{"sh", "-c", `test -f "$0/pet.go" && exit 1`}))
+	require.ErrorContains(t, err, "post command")
+	require.Equal(t, original, readDir(t, schemas))
+	entries, err := os.ReadDir(parent)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	written, err := entimport.WriteSchema(mutations, entimport.WithSchemaPath(schemas), entimport.WithAtomicWrite(true))
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(schemas, "pet.go"), filepath.Join(schemas, "user.go")}, written)
+	files := readDir(t, schemas)
+	require.Contains(t, files, "pet.go")
+	require.NotEqual(t, original["user.go"], files["user.go"])
+	entries, err = os.ReadDir(parent)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	// Existing directories keep their permissions.
+	require.NoError(t, os.Chmod(schemas, 0750))
+	_, err = entimport.WriteSchema(mutations, entimport.WithSchemaPath(schemas), entimport.WithAtomicWrite(true))
+	require.NoError(t, err)
+	info, err = os.Stat(schemas)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0750), info.Mode().Perm())
+}
+
+func TestWithJoinTableActions(t *testing.T) {
+	files := importSchema(t, dialect.MySQL, MockMySQLM2MTwoTypes(), entimport.WithJoinTableActions(true))
+	require.Equal(t, `func (Group) Edges() []ent.Edge {
+	return []ent.Edge{edge.To("users", User.Type).Annotations(entsql.Annotation{OnDelete: entsql.Cascade})}
+}`, printMethod(t, files["group.go"], "Group", "Edges"))
+	require.Equal(t, `func (User) Edges() []ent.Edge {
+	return []ent.Edge{edge.From("groups", Group.Type).Ref("users")}
+}`, printMethod(t, files["user.go"], "User", "Edges"))
+	// Foreign keys with different actions are not annotated.
+	mock := MockMySQLM2MTwoTypes()
+	mock.Tables[2].ForeignKeys[1].OnDelete = "SET NULL"
+	files = importSchema(t, dialect.MySQL, mock, entimport.WithJoinTableActions(true))
+	require.Equal(t, `func (Group) Edges() []ent.Edge {
+	return []ent.Edge{edge.To("users", User.Type)}
+}`, printMethod(t, files["group.go"], "Group", "Edges"))
+}
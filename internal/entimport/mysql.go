@@ -4,13 +4,17 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"strings"
 
 	"ariga.io/atlas/sql/mysql"
 	"ariga.io/atlas/sql/schema"
 
 	"entgo.io/contrib/schemast"
 	"entgo.io/ent"
+	"entgo.io/ent/dialect"
 	"entgo.io/ent/schema/field"
+	"github.com/google/uuid"
 )
 
 const (
@@ -21,6 +25,14 @@ const (
 	mBigInt    = "bigint"    // MYSQL_TYPE_LONGLONG
 )
 
+// Generated is a column attribute holding the expression of a generated column (GENERATED ALWAYS AS), e.g.
+// json_extract(`payload`,_utf8mb4'$.price'), and if its values are STORED or VIRTUAL (computed when read).
+type Generated struct {
+	schema.Attr
+	Expr   string
+	Stored bool
+}
+
 // MySQL holds the schema import options and an Atlas inspector instance
 type MySQL struct {
 	*ImportOptions
@@ -56,37 +68,100 @@ func (m *MySQL) SchemaMutations(ctx context.Context) ([]schemast.Mutator, error)
 			}
 		}
 	}
-	return schemaMutations(m.field, tables)
+	return schemaMutations(m.ImportOptions, m.field, tables)
 }
 
 func (m *MySQL) field(column *schema.Column) (f ent.Field, err error) {
 	name := column.Name
+	if column.Type == nil || column.Type.Type == nil {
+		return nil, fmt.Errorf("entimport: missing type for column %v", column.Name)
+	}
 	switch typ := column.Type.Type.(type) {
 	case *schema.BinaryType:
-		f = field.Bytes(name)
+		f = m.convertBinary(typ, name)
 	case *schema.BoolType:
 		f = field.Bool(name)
+		boolDefault(f, column)
 	case *schema.DecimalType:
-		f = field.Float(name)
+		f = decimalField(dialect.MySQL, typ, name)
 	case *schema.EnumType:
-		f = field.Enum(name).Values(typ.Values...)
+		f = enumField(name, typ.Values)
+		enumDefault(f, column, typ.Values)
 	case *schema.FloatType:
 		f = m.convertFloat(typ, name)
 	case *schema.IntegerType:
-		f = m.convertInteger(typ, name)
+		f = m.convertInteger(typ, column.Type.Raw, name)
 	case *schema.JSONType:
 		f = field.JSON(name, json.RawMessage{})
 	case *schema.StringType:
-		f = field.String(name)
+		f = m.convertString(typ, name)
 	case *schema.TimeType:
 		f = field.Time(name)
+	case *mysql.SetType:
+		f = m.convertSet(typ, column)
 	default:
 		return nil, fmt.Errorf("entimport: unsupported type %q for column %v", typ, column.Name)
 	}
 	applyColumnAttributes(f, column)
+	generatedColumn(f, column)
 	return f, err
 }
 
+// reJSONExtract matches the JSON path extraction of generated columns, as it is stored by MySQL for both
+// json_extract calls and the -> and ->> operators: json_extract(`payload`,_utf8mb4'$.price').
+var reJSONExtract = regexp.MustCompile("(?i)json_extract\\(\\s*`?(\\w+)`?\\s*,\\s*(?:_\\w+)?'([^']*)'\\s*\\)")
+
+// generatedColumn maps generated columns to optional and immutable fields of their declared type, as their values
+// are computed by the database and cannot be set by ent. The generation expression is added as a comment.
+func generatedColumn(f ent.Field, column *schema.Column) {
+	for _, attr := range column.Attrs {
+		g, ok := attr.(*Generated)
+		if !ok {
+			continue
+		}
+		kind := "VIRTUAL"
+		if g.Stored {
+			kind = "STORED"
+		}
+		text := fmt.Sprintf("entimport: column %q is a %s generated column (%s), and does not accept explicit values", column.Name, kind, g.Expr)
+		if m := reJSONExtract.FindStringSubmatch(g.Expr); m != nil {
+			text = fmt.Sprintf("entimport: column %q is a %s generated column extracting the JSON path %s of column %q (%s), and does not accept explicit values",
+				column.Name, kind, m[2], m[1], g.Expr)
+		}
+		desc := f.Descriptor()
+		desc.Optional, desc.Immutable, desc.Default = true, true, nil
+		desc.Annotations = append(desc.Annotations, &commentAnnotation{Text: text})
+	}
+}
+
+// SET columns are mapped to string fields holding the comma-separated members of the set (e.g. "a,c"), keeping their
+// type in the database. They are not mapped to JSON fields (e.g. field.Strings), as MySQL returns SET values as
+// comma-separated strings and not as JSON arrays. The allowed members are added as a comment.
+func (m *MySQL) convertSet(typ *mysql.SetType, column *schema.Column) ent.Field {
+	t := column.Type.Raw
+	if t == "" {
+		values := make([]string, len(typ.Values))
+		for i, v := range typ.Values {
+			values[i] = "'" + strings.ReplaceAll(v, "'", "''") + "'"
+		}
+		t = "set(" + strings.Join(values, ",") + ")"
+	}
+	f := field.String(column.Name).
+		SchemaType(map[string]string{
+			dialect.MySQL: t, // Override MySQL.
+		})
+	desc := f.Descriptor()
+	desc.Annotations = append(desc.Annotations, &commentAnnotation{
+		Text: fmt.Sprintf("entimport: column %q is a SET of %s, stored as a comma-separated string", column.Name, strings.Join(typ.Values, ", ")),
+	})
+	if d, ok := column.Default.(*schema.Literal); ok {
+		if v, ok := unquote(d.V); ok {
+			desc.Default = v
+		}
+	}
+	return f
+}
+
 func (m *MySQL) convertFloat(typ *schema.FloatType, name string) (f ent.Field) {
 	// A precision from 0 to 23 results in a 4-byte single-precision FLOAT column.
 	// A precision from 24 to 53 results in an 8-byte double-precision DOUBLE column:
@@ -97,34 +172,69 @@ func (m *MySQL) convertFloat(typ *schema.FloatType, name string) (f ent.Field) {
 	return field.Float32(name)
 }
 
-func (m *MySQL) convertInteger(typ *schema.IntegerType, name string) (f ent.Field) {
-	if typ.Unsigned {
-		switch typ.T {
-		case mTinyInt:
-			f = field.Uint8(name)
-		case mSmallInt:
-			f = field.Uint16(name)
-		case mMediumInt:
-			f = field.Uint32(name)
-		case mInt:
-			f = field.Uint32(name)
-		case mBigInt:
-			f = field.Uint64(name)
-		}
+// Binary columns of 16 bytes are mapped to UUID fields if configured (see WithBinary16AsUUID). Variable-length
+// binary columns are always mapped to bytes fields.
+func (m *MySQL) convertBinary(typ *schema.BinaryType, name string) ent.Field {
+	if m.binary16UUID && typ.T == mysql.TypeBinary && typ.Size == 16 {
+		return field.UUID(name, uuid.UUID{}).
+			SchemaType(map[string]string{
+				dialect.MySQL: "binary(16)", // Override MySQL.
+			})
+	}
+	return field.Bytes(name)
+}
+
+// Text columns are mapped to text fields, keeping their type in the database, as ent creates text fields
+// as longtext: https://dev.mysql.com/doc/refman/8.0/en/blob.html
+func (m *MySQL) convertString(typ *schema.StringType, name string) ent.Field {
+	switch typ.T {
+	case mysql.TypeTinyText, mysql.TypeText, mysql.TypeMediumText, mysql.TypeLongText:
+		f := field.Text(name).
+			SchemaType(map[string]string{
+				dialect.MySQL: typ.T, // Override MySQL.
+			})
+		desc := f.Descriptor()
+		desc.Annotations = append(desc.Annotations, &typeAnnotation{Func: "Text"})
 		return f
 	}
+	return field.String(name)
+}
+
+func (m *MySQL) convertInteger(typ *schema.IntegerType, raw, name string) (f ent.Field) {
+	colType := typ.T
+	zerofill := false
+	for _, attr := range typ.Attrs {
+		switch a := attr.(type) {
+		case *mysql.DisplayWidth:
+			colType = fmt.Sprintf("%s(%d)", typ.T, a.N)
+		case *mysql.ZeroFill:
+			zerofill = true
+		}
+	}
+	if typ.Unsigned {
+		colType += " unsigned"
+	}
 	switch typ.T {
 	case mTinyInt:
-		f = field.Int8(name)
+		// A display width of 1 is the convention for boolean columns, regardless of their signedness.
+		if strings.HasPrefix(raw, mTinyInt+"(1)") {
+			return field.Bool(name)
+		}
+		f = m.integerField(name, 8, typ.Unsigned, dialect.MySQL, colType)
 	case mSmallInt:
-		f = field.Int16(name)
+		f = m.integerField(name, 16, typ.Unsigned, dialect.MySQL, colType)
 	case mMediumInt:
-		f = field.Int32(name)
+		f = m.integerField(name, 32, typ.Unsigned, dialect.MySQL, colType)
 	case mInt:
-		f = field.Int32(name)
+		f = m.integerField(name, 32, typ.Unsigned, dialect.MySQL, colType)
 	case mBigInt:
-		// Int64 is not used on purpose.
-		f = field.Int(name)
+		f = m.integerField(name, 64, typ.Unsigned, dialect.MySQL, colType)
+	}
+	// The zerofill attribute (and the display width it pads to) is kept for round-tripping the DDL.
+	if zerofill && f != nil {
+		f.Descriptor().SchemaType = map[string]string{
+			dialect.MySQL: colType + " zerofill", // Override MySQL.
+		}
 	}
 	return f
 }
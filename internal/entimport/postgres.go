@@ -2,8 +2,13 @@ package entimport
 
 import (
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
 
 	"ariga.io/atlas/sql/postgres"
 	"ariga.io/atlas/sql/schema"
@@ -21,6 +26,24 @@ type Postgres struct {
 	*ImportOptions
 }
 
+// Inherits is a table attribute holding the name of a parent table of a table created
+// with the INHERITS clause. The inherited columns are imported as a mixin of the parent.
+type Inherits struct {
+	schema.Attr
+	Parent string
+}
+
+// inheritsQuery returns the inheriting tables of the schema and their parent tables. Partitions,
+// which are also recorded in pg_inherits, are skipped by the kind of their parent table.
+const inheritsQuery = `
+SELECT c.relname, p.relname
+FROM pg_inherits i
+JOIN pg_class c ON c.oid = i.inhrelid
+JOIN pg_class p ON p.oid = i.inhparent
+JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE n.nspname = $1 AND c.relkind = 'r' AND p.relkind = 'r'
+ORDER BY c.relname, i.inhseqno`
+
 // NewPostgreSQL - returns a new *Postgres.
 func NewPostgreSQL(i *ImportOptions) (SchemaImporter, error) {
 	return &Postgres{
@@ -30,10 +53,25 @@ func NewPostgreSQL(i *ImportOptions) (SchemaImporter, error) {
 
 // SchemaMutations implements SchemaImporter.
 func (p *Postgres) SchemaMutations(ctx context.Context) ([]schemast.Mutator, error) {
+	schemas := p.schemas
+	if len(schemas) == 0 {
+		schemas = p.driver.Schemas
+	}
+	if len(schemas) > 1 {
+		tables, err := p.inspectSchemas(ctx, schemas)
+		if err != nil {
+			return nil, err
+		}
+		return schemaMutations(p.ImportOptions, p.field, tables)
+	}
+	name := p.driver.SchemaName
+	if len(schemas) == 1 {
+		name = schemas[0]
+	}
 	inspectOptions := &schema.InspectOptions{
 		Tables: p.tables,
 	}
-	s, err := p.driver.InspectSchema(ctx, p.driver.SchemaName, inspectOptions)
+	s, err := p.driver.InspectSchema(ctx, name, inspectOptions)
 	if err != nil {
 		return nil, err
 	}
@@ -51,38 +89,142 @@ func (p *Postgres) SchemaMutations(ctx context.Context) ([]schemast.Mutator, err
 			}
 		}
 	}
-	return schemaMutations(p.field, tables)
+	if err := p.inheritance(ctx, name, tables); err != nil {
+		return nil, err
+	}
+	return schemaMutations(p.ImportOptions, p.field, tables)
+}
+
+// inspectSchemas returns the tables of the given schemas, where foreign keys across the schemas reference the
+// tables they are linked to. Tables are selected (see WithTables and WithExcludedTables) by their names, or by
+// their names qualified with their schemas (e.g. "billing.invoices"). Tables that have the same names as tables
+// of previous schemas are renamed with the prefix of their schema (e.g. "billing_users"), and keep their names
+// in the database.
+func (p *Postgres) inspectSchemas(ctx context.Context, schemas []string) ([]*schema.Table, error) {
+	realm, err := p.driver.InspectRealm(ctx, &schema.InspectRealmOption{Schemas: schemas})
+	if err != nil {
+		return nil, err
+	}
+	match := func(names []string, s *schema.Schema, t *schema.Table) bool {
+		for _, n := range names {
+			if n == t.Name || n == s.Name+"."+t.Name {
+				return true
+			}
+		}
+		return false
+	}
+	var (
+		tables []*schema.Table
+		taken  = make(map[string]bool)
+	)
+	for _, name := range schemas {
+		s, ok := realm.Schema(name)
+		if !ok {
+			return nil, fmt.Errorf("entimport: schema %q was not found", name)
+		}
+		var selected []*schema.Table
+		for _, t := range s.Tables {
+			if len(p.tables) > 0 && !match(p.tables, s, t) || match(p.excludedTables, s, t) {
+				continue
+			}
+			selected = append(selected, t)
+		}
+		if err := p.inheritance(ctx, s.Name, selected); err != nil {
+			return nil, err
+		}
+		for _, t := range selected {
+			if taken[t.Name] {
+				t.Attrs = append(t.Attrs, &renamedTable{Schema: s.Name, Name: t.Name})
+				t.Name = s.Name + "_" + t.Name
+			}
+			taken[t.Name] = true
+			tables = append(tables, t)
+		}
+	}
+	return tables, nil
+}
+
+// inheritance adds the Inherits attributes to the tables created with the INHERITS clause. Atlas does
+// not inspect table inheritance, and it is queried in case the inspector is able to query the database.
+func (p *Postgres) inheritance(ctx context.Context, schemaName string, tables []*schema.Table) error {
+	db, ok := p.driver.Inspector.(schema.ExecQuerier)
+	if !ok {
+		return nil
+	}
+	byName := make(map[string]*schema.Table, len(tables))
+	for _, t := range tables {
+		byName[t.Name] = t
+	}
+	rows, err := db.QueryContext(ctx, inheritsQuery, schemaName)
+	if err != nil {
+		return fmt.Errorf("entimport: query table inheritance: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var child, parent string
+		if err := rows.Scan(&child, &parent); err != nil {
+			return fmt.Errorf("entimport: scan table inheritance: %w", err)
+		}
+		if t, ok := byName[child]; ok {
+			t.Attrs = append(t.Attrs, &Inherits{Parent: parent})
+		}
+	}
+	return rows.Err()
 }
 
 func (p *Postgres) field(column *schema.Column) (f ent.Field, err error) {
 	name := column.Name
+	if column.Type == nil || column.Type.Type == nil {
+		return nil, fmt.Errorf("entimport: missing type for column %v", column.Name)
+	}
 	switch typ := column.Type.Type.(type) {
 	case *schema.BinaryType:
 		f = field.Bytes(name)
+		bytesDefault(f, column)
 	case *schema.BoolType:
 		f = field.Bool(name)
+		boolDefault(f, column)
 	case *schema.DecimalType:
-		f = field.Float(name)
+		f = p.convertDecimal(typ, name)
 	case *schema.EnumType:
-		f = field.Enum(name).Values(typ.Values...)
+		f = enumField(name, typ.Values)
+		enumDefault(f, column, typ.Values)
 	case *schema.FloatType:
 		f = p.convertFloat(typ, name)
 	case *schema.IntegerType:
 		f = p.convertInteger(typ, name)
 	case *schema.JSONType:
-		f = field.JSON(name, json.RawMessage{})
+		// The type is kept to distinguish json from jsonb (binary) columns.
+		f = field.JSON(name, json.RawMessage{}).
+			SchemaType(map[string]string{
+				dialect.Postgres: typ.T, // Override Postgres.
+			})
 	case *schema.StringType:
-		f = field.String(name)
+		f = p.convertString(typ, name)
 	case *schema.TimeType:
-		f = field.Time(name)
+		f = p.convertTime(typ, name)
 	case *postgres.SerialType:
 		f = p.convertSerial(typ, name)
 	case *postgres.UUIDType:
-		f = field.UUID(name, uuid.New())
+		f = field.UUID(name, uuid.UUID{})
+	case *postgres.ArrayType:
+		if f = p.convertArray(typ, name); f == nil {
+			return nil, fmt.Errorf("entimport: unsupported type %q for column %v", typ.T, column.Name)
+		}
+		arrayDefault(f, column)
+	case *postgres.UserDefinedType:
+		if f = p.convertUserDefined(typ, name); f == nil {
+			return nil, fmt.Errorf("entimport: unsupported type %q for column %v", typ.T, column.Name)
+		}
+	case *schema.UnsupportedType:
+		if f = p.convertUnsupported(typ, name); f == nil {
+			return nil, fmt.Errorf("entimport: unsupported type %q for column %v", typ.T, column.Name)
+		}
 	default:
 		return nil, fmt.Errorf("entimport: unsupported type %q for column %v", typ, column.Name)
 	}
 	applyColumnAttributes(f, column)
+	identityGeneration(f, column)
 	return f, err
 }
 
@@ -90,6 +232,46 @@ func (p *Postgres) field(column *schema.Column) (f ent.Field, err error) {
 // up to 16383 digits after the decimal point.
 // real - 4 bytes variable-precision, inexact 6 decimal digits precision.
 // double -	8 bytes	variable-precision, inexact	15 decimal digits precision.
+// Numeric columns without precision may hold values that exceed float64, and are optionally kept as strings.
+// Numeric columns with precision are mapped by decimalField.
+func (p *Postgres) convertDecimal(typ *schema.DecimalType, name string) ent.Field {
+	if typ.Precision > 0 {
+		return decimalField(dialect.Postgres, typ, name)
+	}
+	if p.numericAsString {
+		return field.String(name).
+			SchemaType(map[string]string{
+				dialect.Postgres: typ.T, // Override Postgres.
+			})
+	}
+	return field.Float(name)
+}
+
+// The fractional seconds precision of time columns is kept in their type, e.g. "timestamp(3) with time zone".
+// Time of day columns (time, timetz) always keep their type, as ent creates time fields as timestamps.
+func (p *Postgres) convertTime(typ *schema.TimeType, name string) ent.Field {
+	t := typ.T
+	if t == "timetz" {
+		t = postgres.TypeTimeWTZ
+	}
+	timeOfDay := t == postgres.TypeTime || t == postgres.TypeTimeWOTZ || t == postgres.TypeTimeWTZ
+	if typ.Precision == 0 && !timeOfDay {
+		return field.Time(name)
+	}
+	if typ.Precision > 0 {
+		var zone string
+		t, zone, _ = strings.Cut(t, " ")
+		t = fmt.Sprintf("%s(%d)", t, typ.Precision)
+		if zone != "" {
+			t += " " + zone
+		}
+	}
+	return field.Time(name).
+		SchemaType(map[string]string{
+			dialect.Postgres: t, // Override Postgres.
+		})
+}
+
 func (p *Postgres) convertFloat(typ *schema.FloatType, name string) (f ent.Field) {
 	if typ.T == postgres.TypeReal {
 		return field.Float32(name)
@@ -101,14 +283,13 @@ func (p *Postgres) convertInteger(typ *schema.IntegerType, name string) (f ent.F
 	switch typ.T {
 	// smallint - 2 bytes small-range integer -32768 to +32767.
 	case "smallint":
-		f = field.Int16(name)
+		f = p.integerField(name, 16, false, dialect.Postgres, typ.T)
 	// integer - 4 bytes typical choice for integer	-2147483648 to +2147483647.
 	case "integer":
-		f = field.Int32(name)
+		f = p.integerField(name, 32, false, dialect.Postgres, typ.T)
 	// bigint - 8 bytes large-range integer	-9223372036854775808 to 9223372036854775807.
 	case "bigint":
-		// Int64 is not used on purpose.
-		f = field.Int(name)
+		f = p.integerField(name, 64, false, dialect.Postgres, typ.T)
 	}
 	return f
 }
@@ -122,3 +303,410 @@ func (p *Postgres) convertSerial(typ *postgres.SerialType, name string) ent.Fiel
 			dialect.Postgres: typ.T, // Override Postgres.
 		})
 }
+
+// Object identifier types are used internally by PostgreSQL as primary keys for various system tables.
+// oid - 4 bytes unsigned integer, and its alias types (regclass, regtype, etc.) that are displayed by name.
+// Opaque system types (pg_lsn, txid_snapshot, etc.) are stored as text by ent, keeping their type in the database.
+func (p *Postgres) convertUnsupported(typ *schema.UnsupportedType, name string) ent.Field {
+	switch typ.T {
+	case "oid":
+		return field.Uint32(name).
+			SchemaType(map[string]string{
+				dialect.Postgres: typ.T, // Override Postgres.
+			})
+	case "regclass", "regcollation", "regconfig", "regdictionary", "regnamespace",
+		"regoper", "regoperator", "regproc", "regprocedure", "regrole", "regtype",
+		"pg_lsn", "pg_snapshot", "txid_snapshot":
+		return field.String(name).
+			SchemaType(map[string]string{
+				dialect.Postgres: typ.T, // Override Postgres.
+			})
+	}
+	return nil
+}
+
+// Fixed-length (blank-padded) character types keep their length as the maximum length of the field,
+// and their type in the database, as ent creates string fields as varchar.
+func (p *Postgres) convertString(typ *schema.StringType, name string) ent.Field {
+	switch typ.T {
+	case postgres.TypeCharacter, postgres.TypeChar, "bpchar":
+		// A character column without length specifier is equivalent to character(1).
+		size := typ.Size
+		if size == 0 {
+			size = 1
+		}
+		f := field.String(name).
+			SchemaType(map[string]string{
+				dialect.Postgres: fmt.Sprintf("char(%d)", size), // Override Postgres.
+			})
+		desc := f.Descriptor()
+		desc.Annotations = append(desc.Annotations, &callAnnotation{
+			Method: "MaxLen",
+			Args:   []string{strconv.Itoa(size)},
+		})
+		return f
+	}
+	return field.String(name)
+}
+
+// Arrays are stored as JSON by ent, keeping their type (and the element size) in the database, e.g.
+// "varchar(255)[]" or "int4[]". Arrays with more than two dimensions are not supported.
+func (p *Postgres) convertArray(typ *postgres.ArrayType, name string) ent.Field {
+	elem, ok := arrayElems[arrayElemType(typ)]
+	dims := strings.Count(typ.T, "[]")
+	if !ok || dims > 2 {
+		return nil
+	}
+	t := elem.typ
+	for i := 0; i < dims; i++ {
+		t = reflect.SliceOf(t)
+	}
+	f := field.JSON(name, reflect.MakeSlice(t, 0, 0).Interface()).
+		SchemaType(map[string]string{
+			dialect.Postgres: typ.T, // Override Postgres.
+		})
+	desc := f.Descriptor()
+	desc.Annotations = append(desc.Annotations, &typeAnnotation{
+		Expr:    strings.Repeat("[]", dims) + elem.expr + "{}",
+		Imports: elem.imports,
+	})
+	return f
+}
+
+// arrayElem describes the Go type of the elements of an array.
+type arrayElem struct {
+	typ     reflect.Type
+	expr    string   // Go type of the elements, e.g. "int".
+	imports []string // Packages used by the Go type.
+	// format returns the Go expression of an element of a default value, or false if it is not supported.
+	format func(string) (string, bool)
+}
+
+var (
+	stringElem = &arrayElem{
+		typ:  reflect.TypeOf(""),
+		expr: "string",
+		format: func(v string) (string, bool) {
+			return strconv.Quote(v), true
+		},
+	}
+	intElem = &arrayElem{
+		typ:  reflect.TypeOf(0),
+		expr: "int",
+		format: func(v string) (string, bool) {
+			n, err := strconv.ParseInt(v, 10, 64)
+			return strconv.FormatInt(n, 10), err == nil
+		},
+	}
+	floatElem = &arrayElem{
+		typ:  reflect.TypeOf(float64(0)),
+		expr: "float64",
+		format: func(v string) (string, bool) {
+			f, err := strconv.ParseFloat(v, 64)
+			return v, err == nil && !math.IsInf(f, 0) && !math.IsNaN(f)
+		},
+	}
+	boolElem = &arrayElem{
+		typ:  reflect.TypeOf(false),
+		expr: "bool",
+		format: func(v string) (string, bool) {
+			switch strings.ToLower(v) {
+			case "t", "true":
+				return "true", true
+			case "f", "false":
+				return "false", true
+			}
+			return "", false
+		},
+	}
+	uuidElem = &arrayElem{
+		typ:     reflect.TypeOf(uuid.UUID{}),
+		expr:    "uuid.UUID",
+		imports: []string{"github.com/google/uuid"},
+		format: func(v string) (string, bool) {
+			_, err := uuid.Parse(v)
+			return fmt.Sprintf("uuid.MustParse(%q)", v), err == nil
+		},
+	}
+	// arrayElems maps the element types of arrays, by their SQL names and their internal names (e.g. "int4"),
+	// as reported by the database for inspected columns.
+	arrayElems = map[string]*arrayElem{
+		postgres.TypeVarChar:   stringElem,
+		postgres.TypeCharVar:   stringElem,
+		postgres.TypeText:      stringElem,
+		postgres.TypeCharacter: stringElem,
+		postgres.TypeChar:      stringElem,
+		"bpchar":               stringElem,
+		postgres.TypeSmallInt:  intElem,
+		postgres.TypeInteger:   intElem,
+		postgres.TypeBigInt:    intElem,
+		postgres.TypeInt:       intElem,
+		postgres.TypeInt2:      intElem,
+		postgres.TypeInt4:      intElem,
+		postgres.TypeInt8:      intElem,
+		postgres.TypeReal:      floatElem,
+		postgres.TypeDouble:    floatElem,
+		postgres.TypeFloat4:    floatElem,
+		postgres.TypeFloat8:    floatElem,
+		postgres.TypeNumeric:   floatElem,
+		postgres.TypeDecimal:   floatElem,
+		postgres.TypeBoolean:   boolElem,
+		postgres.TypeBool:      boolElem,
+		postgres.TypeUUID:      uuidElem,
+	}
+)
+
+// arrayElemType returns the type of the array elements without their size, e.g. "varchar" for "varchar(255)[]".
+func arrayElemType(typ *postgres.ArrayType) string {
+	elem := strings.TrimRight(typ.T, "[]")
+	if i := strings.IndexByte(elem, '('); i != -1 {
+		elem = elem[:i]
+	}
+	return strings.TrimSpace(elem)
+}
+
+// User-defined types that are stored as text by ent, keeping their type in the database.
+// ltree - labels of data stored in a hierarchical tree-like structure (ltree extension).
+func (p *Postgres) convertUserDefined(typ *postgres.UserDefinedType, name string) ent.Field {
+	switch typ.T {
+	case "ltree":
+		return field.String(name).
+			SchemaType(map[string]string{
+				dialect.Postgres: typ.T, // Override Postgres.
+			})
+	}
+	return nil
+}
+
+// identityGeneration adds a comment on identity columns that are GENERATED ALWAYS, as ent has no annotation for
+// the generation mode, and creates identity columns as GENERATED BY DEFAULT.
+func identityGeneration(f ent.Field, column *schema.Column) {
+	for _, attr := range column.Attrs {
+		if id, ok := attr.(*postgres.Identity); ok && strings.EqualFold(id.Generation, "ALWAYS") {
+			desc := f.Descriptor()
+			desc.Annotations = append(desc.Annotations, &commentAnnotation{
+				Text: fmt.Sprintf("entimport: column %q is GENERATED ALWAYS AS IDENTITY, and does not accept explicit values", column.Name),
+			})
+		}
+	}
+}
+
+// bytesDefault sets the default value of a bytea column on its field. Values in the hex format ('\x0001')
+// and in the escape format ('a\001') are added as []byte literals, and other expressions as a comment.
+func bytesDefault(f ent.Field, column *schema.Column) {
+	var v string
+	switch x := column.Default.(type) {
+	case nil:
+		return
+	case *schema.Literal:
+		v = x.V
+	case *schema.RawExpr:
+		v = strings.TrimSuffix(x.X, "::bytea")
+	}
+	desc := f.Descriptor()
+	b, err := decodeBytea(v)
+	if err != nil {
+		desc.Annotations = append(desc.Annotations, &commentAnnotation{
+			Text: fmt.Sprintf("entimport: default value %s of column %q is not supported", columnDefault(column), column.Name),
+		})
+		return
+	}
+	desc.Annotations = append(desc.Annotations, &callAnnotation{
+		Method: "Default",
+		Args:   []string{fmt.Sprintf("%#v", b)},
+	})
+}
+
+// arrayDefault sets the default value of an array column on its field. Array literals ('{a,b}'), which may be
+// casted (e.g. '{}'::text[]), and ARRAY constructors of string literals (ARRAY['a'::text]) are added as []string
+// literals. Other expressions are added as a comment, and function calls are left to funcDefault.
+func arrayDefault(f ent.Field, column *schema.Column) {
+	x := columnDefault(column)
+	if _, _, call := defaultFuncName(column); x == "" || call {
+		return
+	}
+	v := strings.TrimSpace(x)
+	// Casts to array types are removed, e.g. '{}'::text[] or (ARRAY['a'::character varying])::character varying[].
+	if idx := strings.LastIndex(v, "::"); idx != -1 {
+		if t := v[idx:]; !strings.Contains(t, "'") && strings.Count(t, "[") == strings.Count(t, "]") {
+			v = v[:idx]
+		}
+	}
+	if len(v) > 1 && v[0] == '(' && v[len(v)-1] == ')' {
+		v = v[1 : len(v)-1]
+	}
+	var (
+		elems []string
+		err   error
+	)
+	if u := strings.ToUpper(v); strings.HasPrefix(u, "ARRAY[") && strings.HasSuffix(u, "]") {
+		elems, err = arrayConstructor(v[len("ARRAY[") : len(v)-1])
+	} else if u, ok := unquote(v); ok && v[0] == '\'' {
+		elems, err = arrayLiteral(strings.ReplaceAll(u, "''", "'"))
+	} else {
+		err = fmt.Errorf("entimport: unexpected array literal %s", v)
+	}
+	desc := f.Descriptor()
+	if err != nil {
+		desc.Annotations = append(desc.Annotations, &commentAnnotation{
+			Text: fmt.Sprintf("entimport: default value %s of column %q is not supported", x, column.Name),
+		})
+		return
+	}
+	typ := column.Type.Type.(*postgres.ArrayType)
+	elem := arrayElems[arrayElemType(typ)]
+	args := make([]string, len(elems))
+	for i, e := range elems {
+		var ok bool
+		if args[i], ok = elem.format(e); !ok {
+			desc.Annotations = append(desc.Annotations, &commentAnnotation{
+				Text: fmt.Sprintf("entimport: default value %s of column %q is not supported", x, column.Name),
+			})
+			return
+		}
+	}
+	desc.Annotations = append(desc.Annotations, &callAnnotation{
+		Method:  "Default",
+		Args:    []string{"[]" + elem.expr + "{" + strings.Join(args, ", ") + "}"},
+		Imports: elem.imports,
+	})
+}
+
+// arrayLiteral parses the elements of a one-dimensional array literal, e.g. {a,"b c"}.
+// NULL elements are not supported, as they cannot be held by Go slices.
+func arrayLiteral(v string) ([]string, error) {
+	if len(v) < 2 || v[0] != '{' || v[len(v)-1] != '}' {
+		return nil, fmt.Errorf("entimport: unexpected array literal %s", v)
+	}
+	elems := []string{}
+	v = strings.TrimSpace(v[1 : len(v)-1])
+	for len(v) > 0 {
+		var elem string
+		if v[0] == '"' {
+			var b strings.Builder
+			i := 1
+			for ; i < len(v) && v[i] != '"'; i++ {
+				if v[i] == '\\' && i+1 < len(v) {
+					i++
+				}
+				b.WriteByte(v[i])
+			}
+			if i == len(v) {
+				return nil, fmt.Errorf("entimport: unterminated array element %s", v)
+			}
+			elem, v = b.String(), strings.TrimSpace(v[i+1:])
+		} else {
+			end := strings.IndexByte(v, ',')
+			if end == -1 {
+				end = len(v)
+			}
+			elem, v = strings.TrimSpace(v[:end]), v[end:]
+			if strings.EqualFold(elem, "NULL") || strings.ContainsAny(elem, "{}") {
+				return nil, fmt.Errorf("entimport: unsupported array element %s", elem)
+			}
+		}
+		elems = append(elems, elem)
+		if v == "" {
+			break
+		}
+		if v[0] != ',' {
+			return nil, fmt.Errorf("entimport: unexpected array element %s", v)
+		}
+		v = strings.TrimSpace(v[1:])
+	}
+	return elems, nil
+}
+
+// arrayConstructor parses the elements of an ARRAY constructor of string, number or boolean literals,
+// e.g. 'a'::text, 'b'::text or 1, 2.
+func arrayConstructor(v string) ([]string, error) {
+	elems := []string{}
+	for v = strings.TrimSpace(v); len(v) > 0; v = strings.TrimSpace(v) {
+		if v[0] != '\'' {
+			end := strings.IndexByte(v, ',')
+			if end == -1 {
+				end = len(v)
+			}
+			elem := v[:end]
+			// The cast of the element (e.g. ::integer) is skipped.
+			if i := strings.Index(elem, "::"); i != -1 {
+				elem = elem[:i]
+			}
+			elem = strings.TrimSpace(elem)
+			if _, err := strconv.ParseFloat(elem, 64); err != nil && !strings.EqualFold(elem, "true") && !strings.EqualFold(elem, "false") {
+				return nil, fmt.Errorf("entimport: unsupported array element %s", v)
+			}
+			elems = append(elems, elem)
+			if end == len(v) {
+				break
+			}
+			v = v[end+1:]
+			continue
+		}
+		var b strings.Builder
+		i := 1
+		for ; i < len(v); i++ {
+			if v[i] == '\'' {
+				if i+1 < len(v) && v[i+1] == '\'' {
+					i++
+				} else {
+					break
+				}
+			}
+			b.WriteByte(v[i])
+		}
+		if i == len(v) {
+			return nil, fmt.Errorf("entimport: unterminated array element %s", v)
+		}
+		elems = append(elems, b.String())
+		// The cast of the element (e.g. ::text) is skipped.
+		end := strings.IndexByte(v[i:], ',')
+		if end == -1 {
+			break
+		}
+		v = v[i+end+1:]
+	}
+	return elems, nil
+}
+
+// decodeBytea decodes a quoted bytea literal.
+func decodeBytea(v string) ([]byte, error) {
+	if len(v) < 2 || v[0] != '\'' || v[len(v)-1] != '\'' {
+		return nil, fmt.Errorf("entimport: unexpected bytea literal %s", v)
+	}
+	v = strings.ReplaceAll(v[1:len(v)-1], "''", "'")
+	if strings.HasPrefix(v, `\x`) {
+		return hex.DecodeString(v[2:])
+	}
+	b := []byte{}
+	for i := 0; i < len(v); i++ {
+		switch {
+		case v[i] != '\\':
+			b = append(b, v[i])
+		case i+1 < len(v) && v[i+1] == '\\':
+			b = append(b, '\\')
+			i++
+		case i+3 < len(v):
+			n, err := strconv.ParseUint(v[i+1:i+4], 8, 8)
+			if err != nil {
+				return nil, fmt.Errorf("entimport: invalid bytea escape %s: %w", v[i:i+4], err)
+			}
+			b = append(b, byte(n))
+			i += 3
+		default:
+			return nil, fmt.Errorf("entimport: invalid bytea escape %s", v[i:])
+		}
+	}
+	return b, nil
+}
+
+// columnDefault returns the default value of the column as written in the database.
+func columnDefault(column *schema.Column) string {
+	switch x := column.Default.(type) {
+	case *schema.Literal:
+		return x.V
+	case *schema.RawExpr:
+		return x.X
+	}
+	return ""
+}
@@ -0,0 +1,603 @@
+package entimport
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"entgo.io/contrib/schemast"
+	"entgo.io/ent"
+	entschema "entgo.io/ent/schema"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// rewriteFunc modifies the syntax tree of a file declaring a generated schema type.
+// It is used for changes schemast is not able to express by itself.
+type rewriteFunc func(fset *token.FileSet, f *ast.File, spec *ast.TypeSpec) error
+
+// rewriteSchema applies the given rewrite functions on the files of the generated types under dir.
+func rewriteSchema(dir string, mutations []schemast.Mutator, rewrites ...rewriteFunc) error {
+	if len(rewrites) == 0 {
+		return nil
+	}
+	names := make(map[string]bool, len(mutations))
+	for _, m := range mutations {
+		if u, ok := m.(*schemast.UpsertSchema); ok {
+			names[u.Name] = true
+		}
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return err
+	}
+	for _, fn := range files {
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, fn, nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+		var changed bool
+		for _, decl := range f.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				ts, ok := spec.(*ast.TypeSpec)
+				if !ok || !names[ts.Name.Name] {
+					continue
+				}
+				for _, rewrite := range rewrites {
+					if err := rewrite(fset, f, ts); err != nil {
+						return err
+					}
+				}
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+		var buf bytes.Buffer
+		if err := format.Node(&buf, fset, f); err != nil {
+			return err
+		}
+		if err := os.WriteFile(fn, buf.Bytes(), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// embedBaseSchema replaces the embedded ent.Schema of a generated type with the given Go type.
+// The type is given in its full form, for example: "github.com/org/project/ent/schema/base.Schema".
+func embedBaseSchema(goType string) rewriteFunc {
+	return func(fset *token.FileSet, f *ast.File, spec *ast.TypeSpec) error {
+		st, ok := spec.Type.(*ast.StructType)
+		if !ok {
+			return nil
+		}
+		for _, fld := range st.Fields.List {
+			if len(fld.Names) != 0 || !isSelector(fld.Type, "ent", "Schema") {
+				continue
+			}
+			idx := strings.LastIndex(goType, ".")
+			if idx == -1 {
+				fld.Type = ast.NewIdent(goType)
+				continue
+			}
+			pkgPath, name := goType[:idx], goType[idx+1:]
+			fld.Type = &ast.SelectorExpr{X: ast.NewIdent(path.Base(pkgPath)), Sel: ast.NewIdent(name)}
+			astutil.AddImport(fset, f, pkgPath)
+		}
+		return nil
+	}
+}
+
+// renamePackage sets the package name of the generated files, as schemast creates new files in the "schema" package.
+func renamePackage(name string) rewriteFunc {
+	return func(_ *token.FileSet, f *ast.File, _ *ast.TypeSpec) error {
+		f.Name.Name = name
+		return nil
+	}
+}
+
+// removeMethod removes the given method of a generated type, and the imports that are no longer used.
+func removeMethod(name string) rewriteFunc {
+	return func(fset *token.FileSet, f *ast.File, spec *ast.TypeSpec) error {
+		decls := f.Decls[:0]
+		for _, decl := range f.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok || fd.Name.Name != name || !isMethodOf(fd, spec.Name.Name) {
+				decls = append(decls, decl)
+				continue
+			}
+			if fd.Doc != nil {
+				comments := f.Comments[:0]
+				for _, c := range f.Comments {
+					if c != fd.Doc {
+						comments = append(comments, c)
+					}
+				}
+				f.Comments = comments
+			}
+		}
+		f.Decls = decls
+		for _, imp := range append([]*ast.ImportSpec(nil), f.Imports...) {
+			p, err := strconv.Unquote(imp.Path.Value)
+			if err == nil && imp.Name == nil && !astutil.UsesImport(f, p) {
+				astutil.DeleteImport(fset, f, p)
+			}
+		}
+		return nil
+	}
+}
+
+// replaceImportPath replaces the imports of the given module path (or its packages) with another module path.
+func replaceImportPath(from, to string) rewriteFunc {
+	return func(_ *token.FileSet, f *ast.File, _ *ast.TypeSpec) error {
+		for _, imp := range f.Imports {
+			p, err := strconv.Unquote(imp.Path.Value)
+			if err != nil {
+				return err
+			}
+			if p == from || strings.HasPrefix(p, from+"/") {
+				imp.Path.Value = strconv.Quote(to + strings.TrimPrefix(p, from))
+			}
+		}
+		return nil
+	}
+}
+
+// isSelector reports if the given expression is the selector x.sel.
+func isSelector(expr ast.Expr, x, sel string) bool {
+	s, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	id, ok := s.X.(*ast.Ident)
+	return ok && id.Name == x && s.Sel.Name == sel
+}
+
+type (
+	// callAnnotation holds a builder method call that is added to a generated field or edge,
+	// for options schemast is not able to print by itself (e.g. a []byte default value).
+	callAnnotation struct {
+		Method  string   // Method name, e.g. "Default".
+		Args    []string // Arguments, as Go expressions.
+		Imports []string // Packages used by the arguments.
+	}
+
+	// commentAnnotation holds a comment that is added to the method declaring the annotated field or edge,
+	// or to the given method of an annotated type.
+	commentAnnotation struct {
+		Method string
+		Text   string
+	}
+
+	// typeAnnotation holds the constructor of a generated field, in case schemast does not print it. For example,
+	// JSON fields are printed with an empty struct (e.g. []string{} for a field created by field.Strings), and text
+	// fields are printed as string fields.
+	typeAnnotation struct {
+		Func    string   // Constructor name, e.g. "Text" (optional).
+		Expr    string   // Go expression of the type argument, e.g. "[]string{}" (optional).
+		Imports []string // Packages used by the expression.
+	}
+
+	// mixinAnnotation holds a mixin that is generated from the given fields and used by the annotated type,
+	// e.g. for the columns a table inherits from its parent table.
+	mixinAnnotation struct {
+		Type   string
+		Fields []ent.Field
+	}
+
+	// directives holds the entimport annotations of a generated type.
+	directives struct {
+		fields   map[string][]*callAnnotation // by field name
+		edges    map[string][]*callAnnotation // by edge name
+		types    map[string]*typeAnnotation   // by field name
+		comments map[string][]string          // by method name
+		mixins   []string                     // by type name
+	}
+)
+
+// Name implements the schema.Annotation interface.
+func (*callAnnotation) Name() string { return "EntimportCall" }
+
+// Name implements the schema.Annotation interface.
+func (*commentAnnotation) Name() string { return "EntimportComment" }
+
+// Name implements the schema.Annotation interface.
+func (*typeAnnotation) Name() string { return "EntimportType" }
+
+// Name implements the schema.Annotation interface.
+func (*mixinAnnotation) Name() string { return "EntimportMixin" }
+
+// extractDirectives removes the entimport annotations from the given mutations, as schemast is not able to print them.
+// It returns the extracted directives by type name, and a function for restoring the annotations of the mutations.
+func extractDirectives(mutations []schemast.Mutator) (map[string]*directives, func()) {
+	var (
+		restore []func()
+		types   = make(map[string]*directives)
+	)
+	// split returns the given annotations without the entimport annotations, which are passed to fn.
+	split := func(annots *[]entschema.Annotation, fn func(entschema.Annotation)) {
+		var (
+			orig = *annots
+			kept []entschema.Annotation
+		)
+		for _, a := range orig {
+			switch a.(type) {
+			case *callAnnotation, *commentAnnotation, *typeAnnotation, *mixinAnnotation:
+				fn(a)
+			default:
+				kept = append(kept, a)
+			}
+		}
+		if len(kept) != len(orig) {
+			*annots = kept
+			restore = append(restore, func() { *annots = orig })
+		}
+	}
+	for _, m := range mutations {
+		u, ok := m.(*schemast.UpsertSchema)
+		if !ok {
+			continue
+		}
+		d := &directives{
+			fields:   make(map[string][]*callAnnotation),
+			edges:    make(map[string][]*callAnnotation),
+			types:    make(map[string]*typeAnnotation),
+			comments: make(map[string][]string),
+		}
+		for _, f := range u.Fields {
+			desc := f.Descriptor()
+			split(&desc.Annotations, func(a entschema.Annotation) {
+				switch a := a.(type) {
+				case *callAnnotation:
+					d.fields[desc.Name] = append(d.fields[desc.Name], a)
+				case *typeAnnotation:
+					d.types[desc.Name] = a
+				case *commentAnnotation:
+					d.comments["Fields"] = append(d.comments["Fields"], a.Text)
+				}
+			})
+		}
+		for _, e := range u.Edges {
+			desc := e.Descriptor()
+			split(&desc.Annotations, func(a entschema.Annotation) {
+				switch a := a.(type) {
+				case *callAnnotation:
+					d.edges[desc.Name] = append(d.edges[desc.Name], a)
+				case *commentAnnotation:
+					d.comments["Edges"] = append(d.comments["Edges"], a.Text)
+				}
+			})
+		}
+		split(&u.Annotations, func(a entschema.Annotation) {
+			switch a := a.(type) {
+			case *commentAnnotation:
+				d.comments[a.Method] = append(d.comments[a.Method], a.Text)
+			case *mixinAnnotation:
+				d.mixins = append(d.mixins, a.Type)
+			}
+		})
+		if len(d.fields) > 0 || len(d.edges) > 0 || len(d.types) > 0 || len(d.comments) > 0 || len(d.mixins) > 0 {
+			types[u.Name] = d
+		}
+	}
+	return types, func() {
+		for _, fn := range restore {
+			fn()
+		}
+	}
+}
+
+// applyDirectives returns a rewrite function that applies the extracted directives on the generated types.
+func applyDirectives(types map[string]*directives) rewriteFunc {
+	return func(fset *token.FileSet, f *ast.File, spec *ast.TypeSpec) error {
+		d, ok := types[spec.Name.Name]
+		if !ok {
+			return nil
+		}
+		for _, decl := range f.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok || !isMethodOf(fd, spec.Name.Name) {
+				continue
+			}
+			calls := d.fields
+			if fd.Name.Name == "Edges" {
+				calls = d.edges
+			}
+			if fd.Name.Name == "Fields" {
+				if err := replaceTypeArgs(fset, f, fd, d.types); err != nil {
+					return err
+				}
+			}
+			if fd.Name.Name == "Fields" || fd.Name.Name == "Edges" {
+				if err := appendCalls(fset, f, fd, calls); err != nil {
+					return err
+				}
+			}
+			if lines := d.comments[fd.Name.Name]; len(lines) > 0 {
+				addComment(fset, f, fd, lines)
+			}
+		}
+		if len(d.mixins) > 0 {
+			return addMixins(fset, f, spec.Name.Name, d.mixins)
+		}
+		return nil
+	}
+}
+
+// addMixins adds the given mixins to the list returned by the Mixin method of the type, and adds the method
+// in case it does not exist. Mixins that are already used by the type are skipped.
+func addMixins(fset *token.FileSet, f *ast.File, typeName string, mixins []string) error {
+	var list *ast.CompositeLit
+	for _, decl := range f.Decls {
+		if fd, ok := decl.(*ast.FuncDecl); ok && fd.Name.Name == "Mixin" && isMethodOf(fd, typeName) {
+			if list = returnedList(fd); list == nil {
+				return fmt.Errorf("entimport: unexpected Mixin method of type %s", typeName)
+			}
+		}
+	}
+	if list == nil {
+		// The method is parsed as a separate file and added last, as the printer places
+		// the comments of the file by their positions.
+		src := fmt.Sprintf("package schema\nfunc (%s) Mixin() []ent.Mixin {\n\treturn []ent.Mixin{}\n}\n", typeName)
+		mf, err := parser.ParseFile(fset, "", src, 0)
+		if err != nil {
+			return err
+		}
+		fd := mf.Decls[0].(*ast.FuncDecl)
+		list = returnedList(fd)
+		f.Decls = append(f.Decls, fd)
+	}
+	used := make(map[string]bool, len(list.Elts))
+	for _, elt := range list.Elts {
+		if lit, ok := elt.(*ast.CompositeLit); ok {
+			if id, ok := lit.Type.(*ast.Ident); ok {
+				used[id.Name] = true
+			}
+		}
+	}
+	for _, m := range mixins {
+		if !used[m] {
+			list.Elts = append(list.Elts, &ast.CompositeLit{Type: ast.NewIdent(m)})
+			used[m] = true
+		}
+	}
+	return nil
+}
+
+// asMixin returns a rewrite function that turns the given generated types into mixins, by embedding
+// mixin.Schema instead of ent.Schema and removing the methods that are not generated for mixins.
+func asMixin(names map[string]bool) rewriteFunc {
+	rewrites := []rewriteFunc{
+		embedBaseSchema("entgo.io/ent/schema/mixin.Schema"),
+		removeMethod("Edges"),
+		removeMethod("Annotations"),
+	}
+	return func(fset *token.FileSet, f *ast.File, spec *ast.TypeSpec) error {
+		if !names[spec.Name.Name] {
+			return nil
+		}
+		for _, rewrite := range rewrites {
+			if err := rewrite(fset, f, spec); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// appendCalls appends the given builder calls to the items returned by the method, by their names.
+func appendCalls(fset *token.FileSet, f *ast.File, fd *ast.FuncDecl, calls map[string][]*callAnnotation) error {
+	list := returnedList(fd)
+	if len(calls) == 0 || list == nil {
+		return nil
+	}
+	for i, elt := range list.Elts {
+		call, ok := elt.(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+		for _, c := range calls[builderName(call)] {
+			args := make([]ast.Expr, 0, len(c.Args))
+			for _, a := range c.Args {
+				x, err := parser.ParseExpr(a)
+				if err != nil {
+					return fmt.Errorf("entimport: invalid argument %q for %s: %w", a, c.Method, err)
+				}
+				args = append(args, x)
+			}
+			call = &ast.CallExpr{
+				Fun:  &ast.SelectorExpr{X: call, Sel: ast.NewIdent(c.Method)},
+				Args: args,
+			}
+			for _, pkg := range c.Imports {
+				astutil.AddImport(fset, f, pkg)
+			}
+		}
+		list.Elts[i] = call
+	}
+	return nil
+}
+
+// replaceTypeArgs replaces the constructors and the type arguments of the fields returned by the method, by their names.
+func replaceTypeArgs(fset *token.FileSet, f *ast.File, fd *ast.FuncDecl, types map[string]*typeAnnotation) error {
+	list := returnedList(fd)
+	if len(types) == 0 || list == nil {
+		return nil
+	}
+	for _, elt := range list.Elts {
+		call, ok := elt.(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+		t, ok := types[builderName(call)]
+		if !ok {
+			continue
+		}
+		inner := constructorCall(call)
+		if sel, ok := inner.Fun.(*ast.SelectorExpr); ok && t.Func != "" {
+			sel.Sel.Name = t.Func
+		}
+		if t.Expr != "" {
+			x, err := parser.ParseExpr(t.Expr)
+			if err != nil {
+				return fmt.Errorf("entimport: invalid type %q: %w", t.Expr, err)
+			}
+			if len(inner.Args) == 2 {
+				inner.Args[1] = x
+			}
+		}
+		for _, pkg := range t.Imports {
+			astutil.AddImport(fset, f, pkg)
+		}
+	}
+	return nil
+}
+
+// constructorCall returns the innermost call of a builder chain, e.g. field.JSON(...) in field.JSON(...).Optional().
+func constructorCall(call *ast.CallExpr) *ast.CallExpr {
+	for {
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return call
+		}
+		inner, ok := sel.X.(*ast.CallExpr)
+		if !ok {
+			return call
+		}
+		call = inner
+	}
+}
+
+// returnedList returns the list literal returned by the given method, or nil if it does not return one.
+func returnedList(fd *ast.FuncDecl) *ast.CompositeLit {
+	if fd.Body == nil || len(fd.Body.List) != 1 {
+		return nil
+	}
+	ret, ok := fd.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return nil
+	}
+	list, _ := ret.Results[0].(*ast.CompositeLit)
+	return list
+}
+
+// fixTypeArgs moves the type argument of UUID and JSON fields back to the field constructor, as schemast
+// passes it to the last builder call of fields with options, e.g. field.UUID("id").Optional(uuid.UUID{}).
+func fixTypeArgs(_ *token.FileSet, f *ast.File, spec *ast.TypeSpec) error {
+	for _, decl := range f.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || fd.Name.Name != "Fields" || !isMethodOf(fd, spec.Name.Name) {
+			continue
+		}
+		list := returnedList(fd)
+		if list == nil {
+			continue
+		}
+		for _, elt := range list.Elts {
+			call, ok := elt.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				continue
+			}
+			inner := constructorCall(call)
+			if inner == call || len(inner.Args) != 1 || !isSelector(inner.Fun, "field", "UUID") && !isSelector(inner.Fun, "field", "JSON") {
+				continue
+			}
+			inner.Args = append(inner.Args, call.Args[len(call.Args)-1])
+			call.Args = call.Args[:len(call.Args)-1]
+		}
+	}
+	return nil
+}
+
+// addComment adds the given lines to the doc comment of the method.
+func addComment(fset *token.FileSet, f *ast.File, fd *ast.FuncDecl, lines []string) {
+	doc := fd.Doc
+	if doc == nil {
+		doc = &ast.CommentGroup{}
+	}
+	// Position the comment right before the method, as the printer places comments by their positions.
+	// The position starts a new line, as the method may follow the previous declaration without an empty line.
+	pos := fd.Pos() - 1
+	splitLine(fset.File(pos), pos)
+	for _, l := range lines {
+		doc.List = append(doc.List, &ast.Comment{Slash: pos, Text: "// " + l})
+	}
+	if fd.Doc == nil {
+		fd.Doc = doc
+		f.Comments = append(f.Comments, doc)
+		sort.Slice(f.Comments, func(i, j int) bool {
+			return f.Comments[i].Pos() < f.Comments[j].Pos()
+		})
+	}
+}
+
+// splitLine makes the given position the start of a new line in the file, if it is not already.
+func splitLine(tf *token.File, pos token.Pos) {
+	offset := tf.Offset(pos)
+	lines := make([]int, 0, tf.LineCount()+1)
+	for l := 1; l <= tf.LineCount(); l++ {
+		start := tf.Offset(tf.LineStart(l))
+		if start == offset {
+			return
+		}
+		if start > offset && (len(lines) == 0 || lines[len(lines)-1] < offset) {
+			lines = append(lines, offset)
+		}
+		lines = append(lines, start)
+	}
+	if len(lines) == 0 || lines[len(lines)-1] < offset {
+		lines = append(lines, offset)
+	}
+	tf.SetLines(lines)
+}
+
+// builderName returns the name of the field or edge constructed by the given builder call.
+func builderName(call *ast.CallExpr) string {
+	for {
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return ""
+		}
+		inner, ok := sel.X.(*ast.CallExpr)
+		if !ok {
+			break
+		}
+		call = inner
+	}
+	if len(call.Args) == 0 {
+		return ""
+	}
+	lit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return ""
+	}
+	name, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return ""
+	}
+	return name
+}
+
+// isMethodOf reports if the given function is a method of the type.
+func isMethodOf(fd *ast.FuncDecl, typeName string) bool {
+	if fd.Recv == nil || len(fd.Recv.List) != 1 {
+		return false
+	}
+	id, ok := fd.Recv.List[0].Type.(*ast.Ident)
+	return ok && id.Name == typeName
+}
@@ -0,0 +1,117 @@
+package entimport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlite"
+
+	"entgo.io/contrib/schemast"
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"github.com/google/uuid"
+)
+
+// SQLite implements SchemaImporter for SQLite databases.
+type SQLite struct {
+	*ImportOptions
+}
+
+// NewSQLite - returns a new *SQLite.
+func NewSQLite(i *ImportOptions) (SchemaImporter, error) {
+	return &SQLite{
+		ImportOptions: i,
+	}, nil
+}
+
+// SchemaMutations implements SchemaImporter.
+func (s *SQLite) SchemaMutations(ctx context.Context) ([]schemast.Mutator, error) {
+	inspectOptions := &schema.InspectOptions{
+		Tables: s.tables,
+	}
+	sc, err := s.driver.InspectSchema(ctx, s.driver.SchemaName, inspectOptions)
+	if err != nil {
+		return nil, err
+	}
+	tables := sc.Tables
+	if s.excludedTables != nil {
+		tables = nil
+		excludedTableNames := make(map[string]bool)
+		for _, t := range s.excludedTables {
+			excludedTableNames[t] = true
+		}
+		// filter out tables that are in excludedTables:
+		for _, t := range sc.Tables {
+			if !excludedTableNames[t.Name] {
+				tables = append(tables, t)
+			}
+		}
+	}
+	// SQLite reports primary key columns that were not declared as NOT NULL as nullable,
+	// although rowid aliases (INTEGER PRIMARY KEY) can never hold NULL values.
+	for _, t := range tables {
+		if t.PrimaryKey == nil {
+			continue
+		}
+		for _, p := range t.PrimaryKey.Parts {
+			if p.C != nil {
+				p.C.Type.Null = false
+			}
+		}
+	}
+	return schemaMutations(s.ImportOptions, s.field, tables)
+}
+
+func (s *SQLite) field(column *schema.Column) (f ent.Field, err error) {
+	name := column.Name
+	if column.Type == nil || column.Type.Type == nil {
+		return nil, fmt.Errorf("entimport: missing type for column %v", column.Name)
+	}
+	switch typ := column.Type.Type.(type) {
+	case *schema.BinaryType:
+		f = field.Bytes(name)
+	case *schema.BoolType:
+		f = field.Bool(name)
+	case *schema.DecimalType:
+		f = field.Float(name)
+	case *schema.FloatType:
+		f = field.Float(name)
+	case *schema.IntegerType:
+		// SQLite stores all integers as signed 64-bit values, regardless of their declared type.
+		f = field.Int(name)
+	case *schema.JSONType:
+		f = field.JSON(name, json.RawMessage{})
+	case *schema.StringType:
+		f = field.String(name)
+	case *schema.TimeType:
+		f = field.Time(name)
+	case *sqlite.UUIDType:
+		f = field.UUID(name, uuid.UUID{})
+	case *schema.UnsupportedType:
+		f = s.affinityField(typ.T, name)
+	default:
+		return nil, fmt.Errorf("entimport: unsupported type %q for column %v", typ, column.Name)
+	}
+	applyColumnAttributes(f, column)
+	return f, err
+}
+
+// affinityField returns a field for column types that are not recognized by Atlas, based on the type
+// affinity SQLite assigns to them: https://www.sqlite.org/datatype3.html#determination_of_column_affinity
+func (s *SQLite) affinityField(typ, name string) ent.Field {
+	t := strings.ToUpper(typ)
+	switch {
+	case strings.Contains(t, "INT"):
+		return field.Int(name)
+	case strings.Contains(t, "CHAR"), strings.Contains(t, "CLOB"), strings.Contains(t, "TEXT"):
+		return field.String(name)
+	case t == "", strings.Contains(t, "BLOB"):
+		return field.Bytes(name)
+	default:
+		// Columns with REAL or NUMERIC affinity.
+		return field.Float(name)
+	}
+}
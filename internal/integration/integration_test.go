@@ -19,6 +19,7 @@ import (
 	"entgo.io/ent/dialect"
 	"github.com/go-openapi/inflect"
 	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/require"
 )
 
@@ -543,7 +544,7 @@ create table user_groups
 			r.NoError(err)
 			mutations, err := si.SchemaMutations(ctx)
 			r.NoError(err)
-			err = entimport.WriteSchema(mutations, entimport.WithSchemaPath(schemas))
+			_, err = entimport.WriteSchema(mutations, entimport.WithSchemaPath(schemas))
 			r.NoError(err)
 			r.NotZero(tt.entities)
 			actualFiles := readDir(t, schemas)
@@ -1124,7 +1125,7 @@ create table user_groups
 			r.NoError(err)
 			mutations, err := si.SchemaMutations(ctx)
 			r.NoError(err)
-			err = entimport.WriteSchema(mutations, entimport.WithSchemaPath(schemas))
+			_, err = entimport.WriteSchema(mutations, entimport.WithSchemaPath(schemas))
 			r.NoError(err)
 			r.NotZero(tt.entities)
 			actualFiles := readDir(t, schemas)
@@ -1151,6 +1152,213 @@ create table user_groups
 	}
 }
 
+func TestSQLite(t *testing.T) {
+	var (
+		r   = require.New(t)
+		ctx = context.Background()
+		dsn = "file:test?mode=memory&cache=shared&_fk=1"
+	)
+	tests := []struct {
+		name           string
+		query          string
+		entities       []string
+		expectedFields map[string]string
+		expectedEdges  map[string]string
+	}{
+		{
+			name: "one table",
+			// language=SQLite
+			query: `
+create table users
+(
+    id   integer primary key autoincrement,
+    age  integer      not null,
+    name varchar(255) not null
+);
+			`,
+			entities: []string{"user"},
+			expectedFields: map[string]string{
+				"user": `func (User) Fields() []ent.Field {
+	return []ent.Field{field.Int("id"), field.Int("age"), field.String("name")}
+}`,
+			},
+			expectedEdges: map[string]string{
+				"user": `func (User) Edges() []ent.Edge {
+	return nil
+}`,
+			},
+		},
+		{
+			name: "type affinity",
+			// language=SQLite
+			query: `
+create table field_types
+(
+    id             integer primary key,
+    small          smallint not null,
+    price          real     not null,
+    amount         numeric  not null,
+    title          text     not null,
+    data           blob     not null,
+    active         boolean  not null,
+    created_at     datetime not null,
+    optional_title text,
+    counter        unsigned integer not null,
+    summary        nvarchar2(20) not null,
+    untyped
+);
+			`,
+			entities: []string{"field_type"},
+			expectedFields: map[string]string{
+				"field_type": `func (FieldType) Fields() []ent.Field {
+	return []ent.Field{field.Int("id"), field.Int("small"), field.Float("price"), field.Float("amount"), field.String("title"), field.Bytes("data"), field.Bool("active"), field.Time("created_at"), field.String("optional_title").Optional(), field.Int("counter"), field.String("summary"), field.Bytes("untyped").Optional()}
+}`,
+			},
+			expectedEdges: map[string]string{
+				"field_type": `func (FieldType) Edges() []ent.Edge {
+	return nil
+}`,
+			},
+		},
+		{
+			name: "o2m relation",
+			// language=SQLite
+			query: `
+create table users
+(
+    id   integer primary key,
+    name text not null
+);
+create table pets
+(
+    id       integer primary key,
+    name     text not null,
+    owner_id integer references users (id)
+);
+			`,
+			entities: []string{"user", "pet"},
+			expectedFields: map[string]string{
+				"user": `func (User) Fields() []ent.Field {
+	return []ent.Field{field.Int("id"), field.String("name")}
+}`,
+				"pet": `func (Pet) Fields() []ent.Field {
+	return []ent.Field{field.Int("id"), field.String("name"), field.Int("owner_id").Optional()}
+}`,
+			},
+			expectedEdges: map[string]string{
+				"user": `func (User) Edges() []ent.Edge {
+	return []ent.Edge{edge.To("pets", Pet.Type)}
+}`,
+				"pet": `func (Pet) Edges() []ent.Edge {
+	return []ent.Edge{edge.From("user", User.Type).Ref("pets").Unique().Field("owner_id")}
+}`,
+			},
+		},
+	}
+
+	// The in-memory database is shared between the connections as long as one of them is open.
+	db, err := sql.Open(dialect.SQLite, dsn)
+	r.NoError(err)
+	defer db.Close()
+	r.NoError(db.Ping())
+	drv, err := mux.Default.OpenImport("sqlite3://" + dsn)
+	r.NoError(err)
+	defer drv.Close()
+	si, err := entimport.NewImport(
+		entimport.WithDriver(drv),
+	)
+	r.NoError(err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := require.New(t)
+			dropSQLite(t, db)
+			schemas := createTempDir(t)
+			_, err := db.ExecContext(ctx, tt.query)
+			r.NoError(err)
+			mutations, err := si.SchemaMutations(ctx)
+			r.NoError(err)
+			_, err = entimport.WriteSchema(mutations, entimport.WithSchemaPath(schemas))
+			r.NoError(err)
+			r.NotZero(tt.entities)
+			actualFiles := readDir(t, schemas)
+			r.EqualValues(len(tt.entities), len(actualFiles))
+			for _, e := range tt.entities {
+				f, err := parser.ParseFile(token.NewFileSet(), "", actualFiles[e+".go"], 0)
+				r.NoError(err)
+				typeName := inflect.Camelize(e)
+				fieldMethod := lookupMethod(f, typeName, "Fields")
+				r.NotNil(fieldMethod)
+				var actualFields bytes.Buffer
+				err = printer.Fprint(&actualFields, token.NewFileSet(), fieldMethod)
+				r.NoError(err)
+				r.EqualValues(tt.expectedFields[e], actualFields.String())
+				edgeMethod := lookupMethod(f, typeName, "Edges")
+				r.NotNil(edgeMethod)
+				var actualEdges bytes.Buffer
+				err = printer.Fprint(&actualEdges, token.NewFileSet(), edgeMethod)
+				r.NoError(err)
+				r.EqualValues(tt.expectedEdges[e], actualEdges.String())
+			}
+		})
+	}
+}
+
+func TestSQLiteWeakEntity(t *testing.T) {
+	var (
+		r   = require.New(t)
+		ctx = context.Background()
+		dsn = "file:weak?mode=memory&cache=shared&_fk=1"
+	)
+	db, err := sql.Open(dialect.SQLite, dsn)
+	r.NoError(err)
+	defer db.Close()
+	// language=SQLite
+	_, err = db.ExecContext(ctx, `
+create table orders
+(
+    id integer primary key
+);
+create table order_items
+(
+    order_id integer not null references orders (id),
+    line     integer not null,
+    quantity integer not null,
+    primary key (order_id, line)
+);
+	`)
+	r.NoError(err)
+	drv, err := mux.Default.OpenImport("sqlite3://" + dsn)
+	r.NoError(err)
+	defer drv.Close()
+	si, err := entimport.NewImport(entimport.WithDriver(drv))
+	r.NoError(err)
+	mutations, err := si.SchemaMutations(ctx)
+	r.NoError(err)
+	schemas := createTempDir(t)
+	_, err = entimport.WriteSchema(mutations, entimport.WithSchemaPath(schemas))
+	r.NoError(err)
+	f, err := parser.ParseFile(token.NewFileSet(), "", readDir(t, schemas)["order_item.go"], 0)
+	r.NoError(err)
+	for method, expected := range map[string]string{
+		"Fields": `func (OrderItem) Fields() []ent.Field {
+	return []ent.Field{field.Int("quantity"), field.Int("order_id").Optional(), field.Int("line")}
+}`,
+		"Edges": `func (OrderItem) Edges() []ent.Edge {
+	return []ent.Edge{edge.From("order", Order.Type).Ref("order_items").Unique().Field("order_id")}
+}`,
+		"Indexes": `func (OrderItem) Indexes() []ent.Index {
+	return []ent.Index{index.Fields("order_id", "line").Unique()}
+}`,
+	} {
+		m := lookupMethod(f, "OrderItem", method)
+		r.NotNil(m)
+		var actual bytes.Buffer
+		r.NoError(printer.Fprint(&actual, token.NewFileSet(), m))
+		r.Equal(expected, actual.String())
+	}
+}
+
 func createTempDir(t *testing.T) string {
 	r := require.New(t)
 	tmpDir, err := ioutil.TempDir("", "entimport-*")
@@ -1198,6 +1406,29 @@ func dropPostgres(t *testing.T, db *sql.DB) {
 	r.NoError(err)
 }
 
+func dropSQLite(t *testing.T, db *sql.DB) {
+	r := require.New(t)
+	t.Log("drop data from database")
+	ctx := context.Background()
+	rows, err := db.QueryContext(ctx, "SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'")
+	r.NoError(err)
+	var tables []string
+	for rows.Next() {
+		var name string
+		r.NoError(rows.Scan(&name))
+		tables = append(tables, name)
+	}
+	r.NoError(rows.Close())
+	_, err = db.ExecContext(ctx, "PRAGMA foreign_keys = off")
+	r.NoError(err)
+	for _, name := range tables {
+		_, err = db.ExecContext(ctx, "DROP TABLE "+name)
+		r.NoError(err)
+	}
+	_, err = db.ExecContext(ctx, "PRAGMA foreign_keys = on")
+	r.NoError(err)
+}
+
 func lookupMethod(file *ast.File, typeName string, methodName string) (m *ast.FuncDecl) {
 	ast.Inspect(file, func(node ast.Node) bool {
 		if decl, ok := node.(*ast.FuncDecl); ok {
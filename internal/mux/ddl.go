@@ -0,0 +1,790 @@
+package mux
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"ariga.io/atlas/sql/schema"
+	"entgo.io/ent/dialect"
+)
+
+type (
+	// ddlToken is a token of an SQL dump. Comments are skipped by the lexer.
+	ddlToken struct {
+		text       string // Text of the token, without the quotes of quoted identifiers.
+		start, end int    // Offsets of the token in the dump.
+		quoted     bool   // Quoted identifier, e.g. `name` or "name".
+		str        bool   // String literal, e.g. 'value'.
+	}
+
+	// ddlParser builds a schema from the CREATE TABLE, CREATE INDEX, ALTER TABLE and COMMENT ON statements of an
+	// SQL dump. Other statements (e.g. INSERT) are skipped.
+	ddlParser struct {
+		src       string
+		dialect   string
+		parseType func(string) (schema.Type, error)
+		schema    *schema.Schema
+		tables    map[string]*schema.Table
+		fks       []*pendingFK
+	}
+
+	// pendingFK is a foreign key whose referenced table is resolved after parsing all statements,
+	// as tables may be referenced before they are created.
+	pendingFK struct {
+		fk         *schema.ForeignKey
+		refTable   string
+		refColumns []string
+	}
+)
+
+// parseDump parses the given SQL dump of the dialect, and returns the schema it creates.
+func parseDump(r io.Reader, name, dlct string, parseType func(string) (schema.Type, error)) (*schema.Schema, error) {
+	buf, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	p := &ddlParser{
+		src:       string(buf),
+		dialect:   dlct,
+		parseType: parseType,
+		schema:    &schema.Schema{Name: name},
+		tables:    make(map[string]*schema.Table),
+	}
+	tokens, err := p.lex()
+	if err != nil {
+		return nil, err
+	}
+	for len(tokens) > 0 {
+		end := 0
+		for end < len(tokens) && tokens[end].text != ";" || end < len(tokens) && tokens[end].quoted {
+			end++
+		}
+		if err := p.statement(tokens[:end]); err != nil {
+			return nil, err
+		}
+		if end < len(tokens) {
+			end++
+		}
+		tokens = tokens[end:]
+	}
+	p.resolveFKs()
+	p.schema.Realm = &schema.Realm{Schemas: []*schema.Schema{p.schema}}
+	return p.schema, nil
+}
+
+// lex splits the dump into tokens, skipping whitespaces and comments.
+func (p *ddlParser) lex() ([]ddlToken, error) {
+	var (
+		s      = p.src
+		tokens []ddlToken
+	)
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '-' && strings.HasPrefix(s[i:], "--"), c == '#' && p.dialect == dialect.MySQL:
+			if j := strings.IndexByte(s[i:], '\n'); j != -1 {
+				i += j + 1
+			} else {
+				i = len(s)
+			}
+		case c == '/' && strings.HasPrefix(s[i:], "/*"):
+			j := strings.Index(s[i+2:], "*/")
+			if j == -1 {
+				return nil, fmt.Errorf("mux: unterminated comment at offset %d of SQL dump", i)
+			}
+			i += j + 4
+		case c == '\'':
+			j, err := p.endQuote(i, '\'')
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, ddlToken{text: s[i:j], start: i, end: j, str: true})
+			i = j
+		case c == '"' || c == '`':
+			j, err := p.endQuote(i, c)
+			if err != nil {
+				return nil, err
+			}
+			q := string(c)
+			tokens = append(tokens, ddlToken{text: strings.ReplaceAll(s[i+1:j-1], q+q, q), start: i, end: j, quoted: true})
+			i = j
+		case c == '$' && p.dialect == dialect.Postgres:
+			// Dollar-quoted strings, e.g. the bodies of functions: $$ ... $$ or $body$ ... $body$.
+			tag := s[i : i+1+strings.IndexByte(s[i+1:], '$')+1]
+			j := strings.Index(s[i+len(tag):], tag)
+			if !strings.HasSuffix(tag, "$") || len(tag) < 2 || j == -1 {
+				return nil, fmt.Errorf("mux: unterminated dollar-quoted string at offset %d of SQL dump", i)
+			}
+			end := i + len(tag) + j + len(tag)
+			tokens = append(tokens, ddlToken{text: s[i:end], start: i, end: end, str: true})
+			i = end
+		case isIdentByte(c):
+			j := i
+			for j < len(s) && (isIdentByte(s[j]) || s[j] == '.' && unicode.IsDigit(rune(c))) {
+				j++
+			}
+			tokens = append(tokens, ddlToken{text: s[i:j], start: i, end: j})
+			i = j
+		default:
+			tokens = append(tokens, ddlToken{text: s[i : i+1], start: i, end: i + 1})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+// endQuote returns the offset after the closing quote of the quoted text that starts at the given offset.
+// Quotes are escaped by doubling them, or by a backslash in MySQL string literals.
+func (p *ddlParser) endQuote(start int, q byte) (int, error) {
+	s := p.src
+	for i := start + 1; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && q == '\'' && p.dialect == dialect.MySQL:
+			i++
+		case s[i] == q && i+1 < len(s) && s[i+1] == q:
+			i++
+		case s[i] == q:
+			return i + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("mux: unterminated quoted text at offset %d of SQL dump", start)
+}
+
+// isIdentByte reports if the byte can be a part of an unquoted identifier, keyword or number.
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '$' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// statement applies the given statement on the schema, in case it creates or alters a table.
+func (p *ddlParser) statement(tokens []ddlToken) error {
+	switch {
+	case hasWords(tokens, "CREATE", "TABLE"):
+		return p.createTable(tokens[2:])
+	case hasWords(tokens, "CREATE", "INDEX"), hasWords(tokens, "CREATE", "UNIQUE", "INDEX"):
+		return p.createIndex(tokens[1:])
+	case hasWords(tokens, "ALTER", "TABLE"):
+		return p.alterTable(tokens[2:])
+	case hasWords(tokens, "COMMENT", "ON"):
+		p.commentOn(tokens[2:])
+	}
+	return nil
+}
+
+// commentOn sets the comment of the table or the column of the given statement (e.g. COMMENT ON TABLE users
+// IS 'text'), starting after the ON keyword.
+func (p *ddlParser) commentOn(tokens []ddlToken) {
+	if len(tokens) < 4 || !isWord(tokens[0], "TABLE") && !isWord(tokens[0], "COLUMN") {
+		return
+	}
+	// The name parts of the object, e.g. "public", "users" and "name" for public.users.name.
+	var parts []string
+	i := 1
+	for ; i < len(tokens) && !isWord(tokens[i], "IS"); i++ {
+		if tokens[i].text != "." || tokens[i].quoted {
+			parts = append(parts, tokens[i].text)
+		}
+	}
+	if i+1 >= len(tokens) || !tokens[i+1].str || len(parts) == 0 {
+		return
+	}
+	comment := unquoteString(tokens[i+1].text)
+	if isWord(tokens[0], "TABLE") {
+		if t, ok := p.tables[parts[len(parts)-1]]; ok {
+			t.Attrs = setComment(t.Attrs, comment)
+		}
+		return
+	}
+	if len(parts) < 2 {
+		return
+	}
+	if t, ok := p.tables[parts[len(parts)-2]]; ok {
+		if c, ok := t.Column(parts[len(parts)-1]); ok {
+			c.Attrs = setComment(c.Attrs, comment)
+		}
+	}
+}
+
+// setComment sets the comment in the given attributes, replacing the existing one.
+func setComment(attrs []schema.Attr, text string) []schema.Attr {
+	for _, a := range attrs {
+		if c, ok := a.(*schema.Comment); ok {
+			c.Text = text
+			return attrs
+		}
+	}
+	return append(attrs, &schema.Comment{Text: text})
+}
+
+// createTable adds the table created by the given statement, starting after the TABLE keyword.
+func (p *ddlParser) createTable(tokens []ddlToken) error {
+	tokens = skipWords(tokens, "IF", "NOT", "EXISTS")
+	name, tokens := qualifiedName(tokens)
+	if name == "" || len(tokens) == 0 || tokens[0].text != "(" {
+		// Tables that are created from queries or other tables (e.g. CREATE TABLE ... AS SELECT) are skipped.
+		return nil
+	}
+	defs, n := group(tokens)
+	t := &schema.Table{Name: name, Schema: p.schema}
+	// The table options that follow the definitions are skipped, except for the comment of the table
+	// (e.g. ENGINE=InnoDB COMMENT='text').
+	for opts := tokens[n:]; len(opts) > 0; opts = opts[1:] {
+		if isWord(opts[0], "COMMENT") {
+			if opts = skipWords(opts[1:], "="); len(opts) > 0 && opts[0].str {
+				t.Attrs = append(t.Attrs, &schema.Comment{Text: unquoteString(opts[0].text)})
+			}
+			break
+		}
+	}
+	p.tables[name] = t
+	p.schema.Tables = append(p.schema.Tables, t)
+	var constraints [][]ddlToken
+	for _, def := range splitList(defs) {
+		if len(def) == 0 {
+			continue
+		}
+		if isConstraint(def) {
+			// Constraints are added after all columns, as they may precede the columns they reference.
+			constraints = append(constraints, def)
+			continue
+		}
+		if err := p.column(t, def); err != nil {
+			return err
+		}
+	}
+	for _, def := range constraints {
+		if err := p.constraint(t, def); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// column adds the column defined by the given tokens to the table, along with its inline constraints.
+func (p *ddlParser) column(t *schema.Table, def []ddlToken) error {
+	c := &schema.Column{Name: def[0].text, Type: &schema.ColumnType{Null: true}}
+	i := 1
+	for i < len(def) && !isColumnAttr(def, i) {
+		if def[i].text == "(" {
+			_, n := group(def[i:])
+			i += n
+			continue
+		}
+		i++
+	}
+	if i == 1 {
+		return fmt.Errorf("mux: missing type of column %s.%s in SQL dump", t.Name, c.Name)
+	}
+	c.Type.Raw = normalizeType(p.src[def[1].start:def[i-1].end])
+	typ, err := p.parseType(c.Type.Raw)
+	if err != nil {
+		return fmt.Errorf("mux: parse type %q of column %s.%s: %w", c.Type.Raw, t.Name, c.Name, err)
+	}
+	c.Type.Type = typ
+	t.Columns = append(t.Columns, c)
+	for i < len(def) {
+		switch {
+		case isWord(def[i], "NOT") && i+1 < len(def) && isWord(def[i+1], "NULL"):
+			c.Type.Null = false
+			i += 2
+		case isWord(def[i], "DEFAULT"):
+			j := i + 1
+			for j < len(def) && (j == i+1 || !isColumnAttr(def, j)) {
+				if def[j].text == "(" {
+					_, n := group(def[j:])
+					j += n
+					continue
+				}
+				j++
+			}
+			if j > i+1 {
+				c.Default = defaultExpr(p.src[def[i+1].start:def[j-1].end])
+			}
+			i = j
+		case isWord(def[i], "PRIMARY") && i+1 < len(def) && isWord(def[i+1], "KEY"):
+			c.Type.Null = false
+			t.PrimaryKey = p.index(t, "", true, []*schema.Column{c})
+			t.PrimaryKey.Name = p.pkName(t)
+			i += 2
+		case isWord(def[i], "UNIQUE"):
+			t.Indexes = append(t.Indexes, p.index(t, p.uniqueName(t, c), true, []*schema.Column{c}))
+			if i++; i < len(def) && isWord(def[i], "KEY") {
+				i++
+			}
+		case isWord(def[i], "REFERENCES"):
+			n, err := p.references(t, "", []*schema.Column{c}, def[i:])
+			if err != nil {
+				return err
+			}
+			i += n
+		case isWord(def[i], "COMMENT") && i+1 < len(def) && def[i+1].str:
+			c.Attrs = append(c.Attrs, &schema.Comment{Text: unquoteString(def[i+1].text)})
+			i += 2
+		case def[i].text == "(":
+			_, n := group(def[i:])
+			i += n
+		default:
+			i++
+		}
+	}
+	return nil
+}
+
+// constraint adds the table constraint defined by the given tokens.
+func (p *ddlParser) constraint(t *schema.Table, def []ddlToken) error {
+	var symbol string
+	if isWord(def[0], "CONSTRAINT") {
+		if len(def) > 1 && !isWord(def[1], "PRIMARY") && !isWord(def[1], "UNIQUE") && !isWord(def[1], "FOREIGN") && !isWord(def[1], "CHECK") {
+			symbol = def[1].text
+			def = def[2:]
+		} else {
+			def = def[1:]
+		}
+	}
+	if len(def) == 0 {
+		return nil
+	}
+	switch {
+	case isWord(def[0], "PRIMARY"):
+		columns, err := p.columnList(t, def)
+		if err != nil || len(columns) == 0 {
+			return err
+		}
+		t.PrimaryKey = p.index(t, symbol, true, columns)
+		if symbol == "" {
+			t.PrimaryKey.Name = p.pkName(t)
+		}
+		for _, c := range columns {
+			c.Type.Null = false
+		}
+	case isWord(def[0], "UNIQUE"), isWord(def[0], "KEY"), isWord(def[0], "INDEX"):
+		unique := isWord(def[0], "UNIQUE")
+		def = skipWords(def[1:], "KEY")
+		def = skipWords(def, "INDEX")
+		name := symbol
+		if len(def) > 0 && def[0].text != "(" {
+			name = def[0].text
+		}
+		columns, err := p.columnList(t, def)
+		if err != nil || len(columns) == 0 {
+			return err
+		}
+		if name == "" {
+			name = columns[0].Name
+			if unique && p.dialect != dialect.MySQL {
+				name = fmt.Sprintf("%s_%s_key", t.Name, columns[0].Name)
+			}
+		}
+		t.Indexes = append(t.Indexes, p.index(t, name, unique, columns))
+	case isWord(def[0], "FOREIGN"):
+		def = skipWords(def[1:], "KEY")
+		if len(def) > 0 && def[0].text != "(" {
+			// MySQL allows naming the index of the foreign key.
+			def = def[1:]
+		}
+		columns, err := p.columnList(t, def)
+		if err != nil || len(columns) == 0 {
+			return err
+		}
+		_, n := group(def)
+		_, err = p.references(t, symbol, columns, def[n:])
+		return err
+	}
+	return nil
+}
+
+// references adds a foreign key of the given columns, defined by the REFERENCES clause the tokens start with.
+// It returns the number of tokens of the clause.
+func (p *ddlParser) references(t *schema.Table, symbol string, columns []*schema.Column, tokens []ddlToken) (int, error) {
+	if len(tokens) == 0 || !isWord(tokens[0], "REFERENCES") {
+		return 0, fmt.Errorf("mux: missing REFERENCES clause of foreign key of table %s in SQL dump", t.Name)
+	}
+	refTable, rest := qualifiedName(tokens[1:])
+	if refTable == "" {
+		return 0, fmt.Errorf("mux: missing referenced table of foreign key of table %s in SQL dump", t.Name)
+	}
+	if symbol == "" {
+		symbol = p.fkName(t, columns)
+	}
+	pfk := &pendingFK{
+		fk:       &schema.ForeignKey{Symbol: symbol, Table: t, Columns: columns},
+		refTable: refTable,
+	}
+	if len(rest) > 0 && rest[0].text == "(" {
+		inner, n := group(rest)
+		for _, part := range splitList(inner) {
+			if len(part) > 0 {
+				pfk.refColumns = append(pfk.refColumns, part[0].text)
+			}
+		}
+		rest = rest[n:]
+	}
+	// Referential actions, e.g. ON DELETE SET NULL.
+	for len(rest) > 2 && isWord(rest[0], "ON") {
+		var action []string
+		j := 2
+		for j < len(rest) && !isWord(rest[j], "ON") && isReferenceWord(rest[j]) {
+			action = append(action, strings.ToUpper(rest[j].text))
+			j++
+		}
+		opt := schema.ReferenceOption(strings.Join(action, " "))
+		switch {
+		case isWord(rest[1], "DELETE"):
+			pfk.fk.OnDelete = opt
+		case isWord(rest[1], "UPDATE"):
+			pfk.fk.OnUpdate = opt
+		}
+		rest = rest[j:]
+	}
+	p.fks = append(p.fks, pfk)
+	t.ForeignKeys = append(t.ForeignKeys, pfk.fk)
+	return len(tokens) - len(rest), nil
+}
+
+// createIndex adds the index created by the given statement, starting after the CREATE keyword.
+func (p *ddlParser) createIndex(tokens []ddlToken) error {
+	unique := isWord(tokens[0], "UNIQUE")
+	if unique {
+		tokens = tokens[1:]
+	}
+	tokens = tokens[1:]
+	var name string
+	if len(tokens) > 0 && !isWord(tokens[0], "ON") {
+		name, tokens = qualifiedName(tokens)
+	}
+	if len(tokens) == 0 || !isWord(tokens[0], "ON") {
+		return nil
+	}
+	tableName, tokens := qualifiedName(tokens[1:])
+	t, ok := p.tables[tableName]
+	if !ok {
+		return nil
+	}
+	for len(tokens) > 0 && tokens[0].text != "(" {
+		tokens = tokens[1:]
+	}
+	columns, err := p.columnList(t, tokens)
+	if err != nil || len(columns) == 0 {
+		return err
+	}
+	t.Indexes = append(t.Indexes, p.index(t, name, unique, columns))
+	return nil
+}
+
+// alterTable applies the ADD and ALTER COLUMN actions of the given statement, starting after the TABLE keyword.
+func (p *ddlParser) alterTable(tokens []ddlToken) error {
+	name, tokens := qualifiedName(skipWords(tokens, "ONLY"))
+	t, ok := p.tables[name]
+	if !ok {
+		return nil
+	}
+	for _, action := range splitList(tokens) {
+		switch {
+		case len(action) < 2:
+		case isWord(action[0], "ADD") && isConstraint(action[1:]):
+			if err := p.constraint(t, action[1:]); err != nil {
+				return err
+			}
+		case isWord(action[0], "ADD"):
+			if err := p.column(t, skipWords(action[1:], "COLUMN")); err != nil {
+				return err
+			}
+		case isWord(action[0], "ALTER"):
+			action = skipWords(action[1:], "COLUMN")
+			if len(action) < 3 {
+				continue
+			}
+			c, ok := t.Column(action[0].text)
+			if !ok {
+				continue
+			}
+			switch {
+			case isWord(action[1], "SET") && isWord(action[2], "DEFAULT") && len(action) > 3:
+				c.Default = defaultExpr(p.src[action[3].start:action[len(action)-1].end])
+			case isWord(action[1], "SET") && isWord(action[2], "NOT") && len(action) > 3 && isWord(action[3], "NULL"):
+				c.Type.Null = false
+			}
+		}
+	}
+	return nil
+}
+
+// columnList returns the columns of the table listed in the first parenthesized group of the tokens.
+// Expressions and columns that do not exist in the table are skipped.
+func (p *ddlParser) columnList(t *schema.Table, tokens []ddlToken) ([]*schema.Column, error) {
+	for len(tokens) > 0 && tokens[0].text != "(" {
+		tokens = tokens[1:]
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("mux: missing columns of constraint of table %s in SQL dump", t.Name)
+	}
+	inner, _ := group(tokens)
+	var columns []*schema.Column
+	for _, part := range splitList(inner) {
+		if len(part) == 0 {
+			continue
+		}
+		if c, ok := t.Column(part[0].text); ok {
+			columns = append(columns, c)
+		}
+	}
+	return columns, nil
+}
+
+// index returns an index of the table on the given columns.
+func (p *ddlParser) index(t *schema.Table, name string, unique bool, columns []*schema.Column) *schema.Index {
+	idx := &schema.Index{Name: name, Unique: unique, Table: t}
+	for i, c := range columns {
+		part := &schema.IndexPart{SeqNo: i + 1, C: c}
+		idx.Parts = append(idx.Parts, part)
+		c.Indexes = append(c.Indexes, idx)
+	}
+	return idx
+}
+
+// resolveFKs links the foreign keys to their referenced tables. Tables that are not created by the dump
+// are referenced by tables that do not belong to the schema.
+func (p *ddlParser) resolveFKs() {
+	for _, pfk := range p.fks {
+		ref, ok := p.tables[pfk.refTable]
+		if !ok {
+			ref = &schema.Table{Name: pfk.refTable}
+			for _, name := range pfk.refColumns {
+				ref.Columns = append(ref.Columns, &schema.Column{Name: name})
+			}
+		}
+		pfk.fk.RefTable = ref
+		// Foreign keys without referenced columns reference the primary key.
+		if len(pfk.refColumns) == 0 && ref.PrimaryKey != nil {
+			for _, part := range ref.PrimaryKey.Parts {
+				pfk.fk.RefColumns = append(pfk.fk.RefColumns, part.C)
+			}
+		}
+		for _, name := range pfk.refColumns {
+			if c, ok := ref.Column(name); ok {
+				pfk.fk.RefColumns = append(pfk.fk.RefColumns, c)
+			}
+		}
+	}
+}
+
+// pkName returns the name of the primary key of the table, as it is named by the database.
+func (p *ddlParser) pkName(t *schema.Table) string {
+	if p.dialect == dialect.MySQL {
+		return "PRIMARY"
+	}
+	return t.Name + "_pkey"
+}
+
+// uniqueName returns the name of the unique index of a column, as it is named by the database.
+func (p *ddlParser) uniqueName(t *schema.Table, c *schema.Column) string {
+	if p.dialect == dialect.MySQL {
+		return c.Name
+	}
+	return fmt.Sprintf("%s_%s_key", t.Name, c.Name)
+}
+
+// fkName returns the name of an unnamed foreign key, as it is named by the database.
+func (p *ddlParser) fkName(t *schema.Table, columns []*schema.Column) string {
+	if p.dialect == dialect.MySQL {
+		return fmt.Sprintf("%s_ibfk_%d", t.Name, len(t.ForeignKeys)+1)
+	}
+	return fmt.Sprintf("%s_%s_fkey", t.Name, columns[0].Name)
+}
+
+// isConstraint reports if the tokens define a table constraint or an index, rather than a column.
+func isConstraint(def []ddlToken) bool {
+	for _, w := range []string{"CONSTRAINT", "PRIMARY", "UNIQUE", "KEY", "INDEX", "FOREIGN", "CHECK", "FULLTEXT", "SPATIAL", "EXCLUDE", "LIKE"} {
+		if isWord(def[0], w) {
+			return true
+		}
+	}
+	return false
+}
+
+// columnAttrs are the keywords that end the type (or the default value) of a column definition.
+var columnAttrs = map[string]bool{
+	"NOT": true, "NULL": true, "DEFAULT": true, "PRIMARY": true, "UNIQUE": true, "REFERENCES": true,
+	"AUTO_INCREMENT": true, "AUTOINCREMENT": true, "COMMENT": true, "CONSTRAINT": true, "CHECK": true,
+	"GENERATED": true, "COLLATE": true, "CHARSET": true, "ON": true, "AS": true, "KEY": true,
+	"VISIBLE": true, "INVISIBLE": true, "SRID": true, "STORAGE": true, "COLUMN_FORMAT": true,
+}
+
+// isColumnAttr reports if the token at the given index starts a column attribute.
+func isColumnAttr(def []ddlToken, i int) bool {
+	t := def[i]
+	if t.quoted || t.str {
+		return false
+	}
+	if strings.EqualFold(t.text, "CHARACTER") {
+		// CHARACTER SET, and not the character (varying) type.
+		return i+1 < len(def) && isWord(def[i+1], "SET")
+	}
+	return columnAttrs[strings.ToUpper(t.text)]
+}
+
+// isReferenceWord reports if the token is a part of a referential action, e.g. SET NULL or NO ACTION.
+func isReferenceWord(t ddlToken) bool {
+	for _, w := range []string{"CASCADE", "RESTRICT", "SET", "NULL", "DEFAULT", "NO", "ACTION"} {
+		if isWord(t, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultExpr returns the default value of a column. Boolean, numeric and quoted values are literals,
+// and other values (e.g. function calls) are raw expressions, as they are inspected by Atlas.
+func defaultExpr(x string) schema.Expr {
+	x = strings.TrimSpace(x)
+	switch u := strings.ToUpper(x); {
+	case u == "TRUE" || u == "FALSE", isNumber(x), len(x) > 1 && x[0] == '\'' && x[len(x)-1] == '\'':
+		return &schema.Literal{V: x}
+	}
+	return &schema.RawExpr{X: x}
+}
+
+// isNumber reports if the string is a numeric literal, e.g. 1, -1 or 0.5.
+func isNumber(s string) bool {
+	s = strings.TrimPrefix(s, "-")
+	if s == "" {
+		return false
+	}
+	dot := false
+	for _, r := range s {
+		switch {
+		case r == '.' && !dot:
+			dot = true
+		case !unicode.IsDigit(r):
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeType returns the raw type of a column in the format of the information schema,
+// i.e. lowercased (except for quoted values) with single spaces.
+func normalizeType(t string) string {
+	var (
+		b      strings.Builder
+		quoted bool
+		space  bool
+	)
+	for _, r := range t {
+		switch {
+		case r == '\'':
+			quoted = !quoted
+		case !quoted && unicode.IsSpace(r):
+			space = true
+			continue
+		case !quoted:
+			r = unicode.ToLower(r)
+		}
+		if space && b.Len() > 0 && r != '(' && r != ')' && r != ',' {
+			b.WriteByte(' ')
+		}
+		space = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// unquoteString returns the value of a quoted string literal.
+func unquoteString(s string) string {
+	s = s[1 : len(s)-1]
+	s = strings.ReplaceAll(s, "''", "'")
+	return strings.ReplaceAll(s, `\'`, "'")
+}
+
+// isWord reports if the token is the given keyword.
+func isWord(t ddlToken, w string) bool {
+	return !t.quoted && !t.str && strings.EqualFold(t.text, w)
+}
+
+// hasWords reports if the tokens start with the given keywords.
+func hasWords(tokens []ddlToken, words ...string) bool {
+	if len(tokens) < len(words) {
+		return false
+	}
+	for i, w := range words {
+		if !isWord(tokens[i], w) {
+			return false
+		}
+	}
+	return true
+}
+
+// skipWords returns the tokens after the given keywords, in case the tokens start with them.
+func skipWords(tokens []ddlToken, words ...string) []ddlToken {
+	if hasWords(tokens, words...) {
+		return tokens[len(words):]
+	}
+	return tokens
+}
+
+// qualifiedName returns the name the tokens start with, without its schema qualifier, and the rest of the tokens.
+func qualifiedName(tokens []ddlToken) (string, []ddlToken) {
+	if len(tokens) == 0 || tokens[0].str || !tokens[0].quoted && !isIdentByte(tokens[0].text[0]) {
+		return "", tokens
+	}
+	name := tokens[0].text
+	tokens = tokens[1:]
+	for len(tokens) > 1 && tokens[0].text == "." && !tokens[0].quoted {
+		name = tokens[1].text
+		tokens = tokens[2:]
+	}
+	return name, tokens
+}
+
+// group returns the tokens inside the parenthesized group the tokens start with, and the number of tokens
+// of the group, including the parentheses.
+func group(tokens []ddlToken) ([]ddlToken, int) {
+	depth := 0
+	for i, t := range tokens {
+		if t.quoted || t.str {
+			continue
+		}
+		switch t.text {
+		case "(":
+			depth++
+		case ")":
+			if depth--; depth == 0 {
+				return tokens[1:i], i + 1
+			}
+		}
+	}
+	if len(tokens) == 0 {
+		return nil, 0
+	}
+	// Unbalanced parentheses, the group ends with the tokens.
+	return tokens[1:], len(tokens)
+}
+
+// splitList splits the tokens by the commas that are not nested in parentheses.
+func splitList(tokens []ddlToken) [][]ddlToken {
+	var (
+		list  [][]ddlToken
+		depth int
+		start int
+	)
+	for i, t := range tokens {
+		if t.quoted || t.str {
+			continue
+		}
+		switch t.text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		case ",":
+			if depth == 0 {
+				list = append(list, tokens[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(list, tokens[start:])
+}
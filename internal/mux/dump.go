@@ -0,0 +1,53 @@
+package mux
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+)
+
+// gzipMagic is the header of gzip compressed files.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// openDump opens an SQL dump file for reading by file-based providers. Gzipped dumps (e.g. "dump.sql.gz")
+// are detected by their header and decompressed transparently.
+func openDump(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	r := bufio.NewReader(f)
+	header, err := r.Peek(len(gzipMagic))
+	if err != nil && err != io.EOF {
+		f.Close()
+		return nil, err
+	}
+	if !bytes.Equal(header, gzipMagic) {
+		return &dumpReader{Reader: r, closers: []io.Closer{f}}, nil
+	}
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &dumpReader{Reader: zr, closers: []io.Closer{zr, f}}, nil
+}
+
+// dumpReader reads a dump, and closes its underlying readers on Close.
+type dumpReader struct {
+	io.Reader
+	closers []io.Closer
+}
+
+// Close implements the io.Closer interface.
+func (d *dumpReader) Close() error {
+	var err error
+	for _, c := range d.closers {
+		if cerr := c.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
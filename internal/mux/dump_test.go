@@ -0,0 +1,180 @@
+package mux
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+const dump = "CREATE TABLE `users` (`id` bigint NOT NULL, PRIMARY KEY (`id`));\n"
+
+func TestOpenDump(t *testing.T) {
+	dir := t.TempDir()
+	plain := filepath.Join(dir, "dump.sql")
+	require.NoError(t, os.WriteFile(plain, []byte(dump), 0600))
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write([]byte(dump))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	gzipped := filepath.Join(dir, "dump.sql.gz")
+	require.NoError(t, os.WriteFile(gzipped, buf.Bytes(), 0600))
+	empty := filepath.Join(dir, "empty.sql")
+	require.NoError(t, os.WriteFile(empty, nil, 0600))
+	for path, expected := range map[string]string{plain: dump, gzipped: dump, empty: ""} {
+		r, err := openDump(path)
+		require.NoError(t, err)
+		b, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, expected, string(b))
+		require.NoError(t, r.Close())
+	}
+	_, err = openDump(filepath.Join(dir, "missing.sql.gz"))
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestDumpProviderGzip(t *testing.T) {
+	dir := t.TempDir()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write([]byte(dump))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	gzipped := filepath.Join(dir, "dump.sql.gz")
+	require.NoError(t, os.WriteFile(gzipped, buf.Bytes(), 0600))
+	plain := filepath.Join(dir, "dump.sql")
+	require.NoError(t, os.WriteFile(plain, []byte(dump), 0600))
+	// Gzipped dumps are detected by their header, and parsed the same as plain ones.
+	for _, path := range []string{gzipped, plain} {
+		drv, err := Default.OpenImport("file://" + path + "?dialect=mysql")
+		require.NoError(t, err)
+		s, err := drv.InspectSchema(context.Background(), drv.SchemaName, nil)
+		require.NoError(t, err)
+		require.Len(t, s.Tables, 1)
+		require.Equal(t, "users", s.Tables[0].Name)
+		require.Equal(t, "id", s.Tables[0].PrimaryKey.Parts[0].C.Name)
+		require.NoError(t, drv.Close())
+	}
+	// Corrupted gzipped dumps fail the provider.
+	require.NoError(t, os.WriteFile(gzipped, buf.Bytes()[:len(buf.Bytes())/2], 0600))
+	_, err = Default.OpenImport("file://" + gzipped + "?dialect=mysql")
+	require.Error(t, err)
+	_, err = Default.OpenImport("file://" + filepath.Join(dir, "missing.sql.gz") + "?dialect=mysql")
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestDumpProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schema.sql")
+	require.NoError(t, os.WriteFile(path, []byte(`
+-- MySQL dump
+/*!40101 SET NAMES utf8mb4 */;
+CREATE TABLE IF NOT EXISTS `+"`users`"+` (
+  `+"`id`"+` bigint NOT NULL AUTO_INCREMENT,
+  `+"`name`"+` varchar(255) NOT NULL DEFAULT 'a;b' COMMENT 'user''s name',
+  `+"`age`"+` int unsigned DEFAULT NULL, # optional
+  PRIMARY KEY (`+"`id`"+`),
+  UNIQUE KEY `+"`name`"+` (`+"`name`"+`)
+) ENGINE=InnoDB COMMENT='registered users';
+CREATE TABLE pets (
+  id bigint NOT NULL PRIMARY KEY,
+  owner_id bigint NULL,
+  created_at timestamp NULL DEFAULT CURRENT_TIMESTAMP
+);
+INSERT INTO pets VALUES (1, NULL, NULL);
+ALTER TABLE pets ADD CONSTRAINT pets_owner FOREIGN KEY (owner_id) REFERENCES users (id) ON DELETE SET NULL;
+`), 0600))
+	drv, err := Default.OpenImport("file://" + path + "?dialect=mysql")
+	require.NoError(t, err)
+	defer drv.Close()
+	require.Equal(t, "mysql", drv.Dialect)
+	s, err := drv.InspectSchema(context.Background(), drv.SchemaName, nil)
+	require.NoError(t, err)
+	require.Len(t, s.Tables, 2)
+	users, pets := s.Tables[0], s.Tables[1]
+	require.Equal(t, "users", users.Name)
+	require.Equal(t, []schema.Attr{&schema.Comment{Text: "registered users"}}, users.Attrs)
+	require.Empty(t, pets.Attrs)
+	require.Len(t, users.Columns, 3)
+	require.Equal(t, "bigint", users.Columns[0].Type.Raw)
+	require.IsType(t, &schema.IntegerType{}, users.Columns[0].Type.Type)
+	require.False(t, users.Columns[0].Type.Null)
+	require.Equal(t, &schema.Literal{V: "'a;b'"}, users.Columns[1].Default)
+	require.Equal(t, []schema.Attr{&schema.Comment{Text: "user's name"}}, users.Columns[1].Attrs)
+	require.Equal(t, &schema.IntegerType{T: "int", Unsigned: true}, users.Columns[2].Type.Type)
+	require.True(t, users.Columns[2].Type.Null)
+	require.Equal(t, users.Columns[:1], []*schema.Column{users.PrimaryKey.Parts[0].C})
+	require.Len(t, users.Indexes, 1)
+	require.True(t, users.Indexes[0].Unique)
+	require.Equal(t, "name", users.Indexes[0].Parts[0].C.Name)
+	require.Equal(t, &schema.RawExpr{X: "CURRENT_TIMESTAMP"}, pets.Columns[2].Default)
+	require.Equal(t, "id", pets.PrimaryKey.Parts[0].C.Name)
+	require.Len(t, pets.ForeignKeys, 1)
+	fk := pets.ForeignKeys[0]
+	require.Equal(t, "pets_owner", fk.Symbol)
+	require.Equal(t, users, fk.RefTable)
+	require.Equal(t, []*schema.Column{pets.Columns[1]}, fk.Columns)
+	require.Equal(t, users.Columns[:1], fk.RefColumns)
+	require.Equal(t, schema.SetNull, fk.OnDelete)
+
+	s, err = drv.InspectSchema(context.Background(), drv.SchemaName, &schema.InspectOptions{Tables: []string{"pets"}})
+	require.NoError(t, err)
+	require.Len(t, s.Tables, 1)
+	require.Equal(t, "pets", s.Tables[0].Name)
+
+	_, err = Default.OpenImport("file://" + path)
+	require.EqualError(t, err, `mux: missing or unsupported dialect "" of SQL dump, for example: "file://schema.sql?dialect=mysql"`)
+}
+
+func TestDumpProviderPostgres(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schema.sql")
+	require.NoError(t, os.WriteFile(path, []byte(`
+CREATE FUNCTION noop() RETURNS trigger AS $$ BEGIN RETURN NEW; END; $$ LANGUAGE plpgsql;
+CREATE TABLE public.groups (
+    id integer NOT NULL,
+    name character varying(255) DEFAULT 'admins'::character varying NOT NULL,
+    CONSTRAINT groups_pkey PRIMARY KEY (id)
+);
+CREATE TABLE public.users (
+    id bigint NOT NULL,
+    group_id integer REFERENCES groups ON DELETE CASCADE,
+    tags text[]
+);
+ALTER TABLE ONLY public.users
+    ADD CONSTRAINT users_pkey PRIMARY KEY (id);
+CREATE UNIQUE INDEX users_group ON public.users USING btree (group_id);
+COMMENT ON TABLE public.users IS 'registered users';
+COMMENT ON COLUMN public.users.tags IS 'the user''s tags';
+COMMENT ON FUNCTION noop() IS 'skipped';
+`), 0600))
+	drv, err := Default.OpenImport("file://" + path + "?dialect=postgres")
+	require.NoError(t, err)
+	require.Equal(t, "public", drv.SchemaName)
+	s, err := drv.InspectSchema(context.Background(), drv.SchemaName, nil)
+	require.NoError(t, err)
+	require.Len(t, s.Tables, 2)
+	groups, users := s.Tables[0], s.Tables[1]
+	require.Equal(t, "character varying(255)", groups.Columns[1].Type.Raw)
+	require.Equal(t, &schema.StringType{T: "character varying", Size: 255}, groups.Columns[1].Type.Type)
+	require.Equal(t, &schema.RawExpr{X: "'admins'::character varying"}, groups.Columns[1].Default)
+	require.False(t, groups.Columns[1].Type.Null)
+	require.Equal(t, "users_pkey", users.PrimaryKey.Name)
+	require.Equal(t, "id", users.PrimaryKey.Parts[0].C.Name)
+	require.Len(t, users.ForeignKeys, 1)
+	require.Equal(t, "users_group_id_fkey", users.ForeignKeys[0].Symbol)
+	require.Equal(t, groups, users.ForeignKeys[0].RefTable)
+	require.Equal(t, groups.Columns[:1], users.ForeignKeys[0].RefColumns)
+	require.Equal(t, schema.Cascade, users.ForeignKeys[0].OnDelete)
+	require.Len(t, users.Indexes, 1)
+	require.Equal(t, "users_group", users.Indexes[0].Name)
+	require.True(t, users.Indexes[0].Unique)
+	require.Equal(t, []schema.Attr{&schema.Comment{Text: "registered users"}}, users.Attrs)
+	require.Equal(t, []schema.Attr{&schema.Comment{Text: "the user's tags"}}, users.Columns[2].Attrs)
+}
@@ -4,17 +4,20 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"unicode"
 
 	"ariga.io/atlas/sql/schema"
 )
 
 type (
-	// importProvider - returns an ImportDriver for a given dialect.
-	importProvider func(string) (*ImportDriver, error)
+	// Provider returns an ImportDriver for the given data source name, without its scheme.
+	Provider func(dsn string) (*ImportDriver, error)
 
 	// Mux is used for routing dsn to correct provider.
 	Mux struct {
-		providers map[string]importProvider
+		providers map[string]Provider
+		// dialects holds the dialect of each scheme, which is the first scheme its provider was registered with.
+		dialects map[string]string
 	}
 
 	// ImportDriver implements Inspector interface and holds inspection information.
@@ -23,22 +26,34 @@ type (
 		schema.Inspector
 		Dialect    string
 		SchemaName string
+		// Schemas holds the schemas to import, in case the data source has several of them (e.g. a Postgres
+		// search_path with multiple entries). SchemaName is the first of them.
+		Schemas []string
 	}
 )
 
 // New returns a new Mux.
 func New() *Mux {
 	return &Mux{
-		providers: make(map[string]importProvider),
+		providers: make(map[string]Provider),
+		dialects:  make(map[string]string),
 	}
 }
 
 var Default = New()
 
-// RegisterProvider is used to register an Atlas provider by key.
-func (u *Mux) RegisterProvider(p importProvider, scheme ...string) {
+// RegisterProvider is used to register an Atlas provider by key. The first scheme is the dialect of the provider,
+// and the others are its aliases. Providers of custom data sources return an ImportDriver holding their own
+// schema.Inspector and dialect, whose columns are mapped to fields by the mapper registered for the dialect
+// with entimport.RegisterFieldMapper. For example:
+//
+//	mux.Default.RegisterProvider(func(dsn string) (*mux.ImportDriver, error) {
+//		return &mux.ImportDriver{Closer: db, Inspector: inspector, Dialect: "clickhouse", SchemaName: "default"}, nil
+//	}, "clickhouse")
+func (u *Mux) RegisterProvider(p Provider, scheme ...string) {
 	for _, s := range scheme {
 		u.providers[s] = p
+		u.dialects[s] = scheme[0]
 	}
 }
 
@@ -55,6 +70,38 @@ func (u *Mux) OpenImport(dsn string) (*ImportDriver, error) {
 	return p(host)
 }
 
+// OpenImportDialect is used for opening an import driver of the given dialect (e.g. "mysql") on a specific data
+// source, which may be given in the format of the database driver, without a scheme. For example:
+// "root:pass@tcp(localhost:3306)/test". A scheme given in the data source must match the dialect.
+func (u *Mux) OpenImportDialect(dialect, dsn string) (*ImportDriver, error) {
+	p, ok := u.providers[dialect]
+	if !ok {
+		return nil, fmt.Errorf("provider does not exist: %q", dialect)
+	}
+	if scheme, host, ok := splitScheme(dsn); ok {
+		if u.dialects[scheme] != u.dialects[dialect] {
+			return nil, fmt.Errorf("mux: scheme %q of the dsn does not match the dialect %q", scheme, dialect)
+		}
+		dsn = host
+	}
+	return p(dsn)
+}
+
+// splitScheme splits the scheme of the data source from the rest of it, in case it has one.
+func splitScheme(dsn string) (string, string, bool) {
+	scheme, rest, ok := strings.Cut(dsn, "://")
+	if !ok || scheme == "" {
+		return "", "", false
+	}
+	for _, r := range scheme {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '+' && r != '-' && r != '.' {
+			// Driver-style data sources may contain "://", e.g. in their parameters.
+			return "", "", false
+		}
+	}
+	return scheme, rest, true
+}
+
 func parseDSN(url string) (string, string, error) {
 	a := strings.SplitN(url, "://", 2)
 	if len(a) != 2 {